@@ -2,39 +2,182 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/kirinyoku/twitch-kit/internal/bot"
+	"github.com/kirinyoku/twitch-kit/internal/cache"
 	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
 	"github.com/kirinyoku/twitch-kit/pkg/config"
 )
 
 func main() {
+	if err := run(); err != nil {
+		log.Printf("%v", err)
+		os.Exit(1)
+	}
+}
+
+// run starts the bot and blocks until it is stopped by SIGINT or SIGTERM.
+// Deferred cleanup such as closing the storage runs before it returns.
+//
+// Returns:
+//
+//	An error if the bot fails to start or does not shut down cleanly
+func run() error {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("failed to load configuration: %v", err)
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	botAPI, err := tgbotapi.NewBotAPI(cfg.TelegramToken)
 	if err != nil {
-		log.Printf("failed to initialize bot: %v", err)
-		return
+		return fmt.Errorf("failed to initialize bot: %w", err)
+	}
+
+	fetcherOpts := []fetcher.Option{
+		fetcher.WithBaseURL(cfg.Fetcher.BaseURL),
+		fetcher.WithUserAgent(cfg.Fetcher.UserAgent),
+		fetcher.WithHTTPClient(&http.Client{Timeout: cfg.Fetcher.Timeout}),
+		fetcher.WithRetryPolicy(fetcher.RetryPolicy(cfg.Fetcher.Retry)),
+		fetcher.WithObserver(func(info fetcher.RequestInfo) {
+			if info.Err != nil {
+				log.Printf("fetcher: %s %s failed after %d attempt(s) in %s: %v", info.Endpoint, info.Username, info.Attempts, info.Duration, info.Err)
+			}
+		}),
+	}
+	for endpoint, path := range cfg.Fetcher.Paths {
+		fetcherOpts = append(fetcherOpts, fetcher.WithEndpointPath(fetcher.Endpoint(endpoint), path))
+	}
+	for endpoint, retry := range cfg.Fetcher.Retries {
+		fetcherOpts = append(fetcherOpts, fetcher.WithEndpointRetryPolicy(fetcher.Endpoint(endpoint), fetcher.RetryPolicy(retry)))
+	}
+
+	upstream := fetcher.NewFetcher(fetcherOpts...)
+	var twitchFetcher bot.Fetcher = upstream
+
+	botOpts := []bot.Option{
+		bot.WithWorkers(cfg.Workers, cfg.QueueSize),
+		bot.WithBatchLimits(cfg.Batch.MaxChannels, cfg.Batch.Concurrency),
+		bot.WithPagination(cfg.Pages.TTL, cfg.Pages.MaxResults),
+		bot.WithWebhook(bot.WebhookConfig(cfg.Webhook)),
+		bot.WithShutdownTimeout(cfg.ShutdownTimeout),
+		bot.WithSendLimits(bot.SendLimits(cfg.Send)),
+		bot.WithRateLimits(bot.RateLimits(cfg.RateLimit)),
+		// Go-live polls only look at liveness, so they skip the cache and history.
+		bot.WithLiveFetcher(upstream),
+	}
+
+	if cfg.CrashChatID != 0 {
+		botOpts = append(botOpts, bot.WithCrashReportChat(cfg.CrashChatID))
+	}
+
+	var (
+		accessStore bot.AccessStore
+		stateStore  bot.StateStore
+	)
+
+	if cfg.Storage.Path != "" {
+		store, err := storage.Open(cfg.Storage.Path, storage.RetentionPolicy{
+			MaxAge:        cfg.Storage.MaxAge,
+			MaxSnapshots:  cfg.Storage.MaxSnapshots,
+			PruneInterval: cfg.Storage.PruneInterval,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open storage: %w", err)
+		}
+		defer func() {
+			if err := store.Close(); err != nil {
+				log.Printf("failed to close storage: %v", err)
+			}
+		}()
+
+		twitchFetcher = storage.NewRecorder(twitchFetcher, store, cfg.Storage.FreshFor)
+		botOpts = append(botOpts,
+			bot.WithHistory(store),
+			// Watchlist polls always reach Twitch but still record snapshots.
+			bot.WithPollFetcher(storage.NewRecorder(upstream, store, 0)),
+			bot.WithWatchlist(store, cfg.Watch.Interval, cfg.Watch.MaxPerChat),
+			bot.WithLiveNotifications(store, cfg.Live.Interval, cfg.Live.MaxPerHour),
+		)
+
+		accessStore = bot.NewPersistentAccessStore(store)
+
+		if cfg.State.Persistent {
+			stateStore = bot.NewPersistentStateStore(store, cfg.State.TTL)
+		}
 	}
 
-	fetcher := fetcher.NewFetcher()
+	if accessStore == nil {
+		accessStore = bot.NewMemoryAccessStore()
+	}
+	if stateStore == nil {
+		stateStore = bot.NewMemoryStateStore(cfg.State.TTL)
+	}
+	botOpts = append(botOpts,
+		bot.WithAccessControl(accessStore, cfg.AdminIDs),
+		bot.WithStateStore(stateStore),
+	)
 
-	tgBot := bot.New(botAPI, fetcher)
-	tgBot.RegisterCommand("start", bot.ViewCmdStart())
+	if cfg.Cache.TTL > 0 {
+		fetcherCache := cache.New(twitchFetcher, cfg.Cache.TTL, cfg.Cache.MaxEntries)
+		defer func() {
+			stats := fetcherCache.Stats()
+			// Misses may have been answered by the recorder from a fresh snapshot.
+			log.Printf("cache: %d hits, %d misses, %d shared, %d entries", stats.Hits, stats.Misses, stats.Shared, stats.Entries)
+		}()
+		twitchFetcher = fetcherCache
+	}
+
+	tgBot := bot.New(botAPI, twitchFetcher, botOpts...)
+	tgBot.RegisterCommand("start", tgBot.ViewCmdStart(), bot.WithDescription("Show the main menu"), bot.WithoutRateLimit())
+	tgBot.RegisterCommand("follows", tgBot.ViewCmdLookup("follows"),
+		bot.WithDescription("Channels a user follows"), bot.WithAliases("following"))
+	tgBot.RegisterCommand("mods", tgBot.ViewCmdLookup("moders"),
+		bot.WithDescription("Moderators of a channel"), bot.WithAliases("moders", "moderators"))
+	tgBot.RegisterCommand("vips", tgBot.ViewCmdLookup("vips"),
+		bot.WithDescription("VIPs of a channel"), bot.WithAliases("vip"))
+	tgBot.RegisterCommand("founders", tgBot.ViewCmdLookup("founders"),
+		bot.WithDescription("Founders of a channel"), bot.WithAliases("founder"))
+	tgBot.RegisterCommand("overlap", tgBot.ViewCmdOverlap(),
+		bot.WithDescription("Mods and VIPs shared between channels"), bot.WithAliases("shared"))
+	tgBot.RegisterCommand("mutual", tgBot.ViewCmdMutual(),
+		bot.WithDescription("Channels two users both follow"), bot.WithAliases("compare"))
+	tgBot.RegisterCommand("export", tgBot.ViewCmdExport(),
+		bot.WithDescription("Download a list as CSV, JSON or XLSX"))
+	tgBot.RegisterCommand("access", tgBot.ViewCmdAccess(), bot.WithoutRateLimit())
+
+	if cfg.Storage.Path != "" {
+		tgBot.RegisterCommand("watch", tgBot.ViewCmdWatch(), bot.WithDescription("Watch a channel's lists for changes"))
+		tgBot.RegisterCommand("unwatch", tgBot.ViewCmdUnwatch(), bot.WithDescription("Stop watching a channel"))
+		tgBot.RegisterCommand("watchlist", tgBot.ViewCmdWatchlist(), bot.WithDescription("List watched channels"))
+		tgBot.RegisterCommand("golive", tgBot.ViewCmdGoLive(), bot.WithDescription("Notify when followed channels go live"))
+		tgBot.RegisterCommand("mute", tgBot.ViewCmdMute(), bot.WithDescription("Mute go-live notifications for a channel"))
+		tgBot.RegisterCommand("unmute", tgBot.ViewCmdUnmute(), bot.WithDescription("Unmute go-live notifications for a channel"))
+		tgBot.RegisterCommand("quiet", tgBot.ViewCmdQuiet(), bot.WithDescription("Set quiet hours for notifications"))
+	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Restore the default signal handling once shutdown begins, so a second
+	// signal terminates the process without waiting for in-flight work.
+	go func() {
+		<-ctx.Done()
+		log.Printf("shutting down, waiting up to %s for in-flight work", cfg.ShutdownTimeout)
+		cancel()
+	}()
+
 	if err := tgBot.Start(ctx); err != nil {
-		log.Printf("failed to start bot: %v", err)
-		return
+		return fmt.Errorf("bot stopped: %w", err)
 	}
+
+	log.Printf("bot stopped, %d panic(s) recovered", tgBot.Panics())
+	return nil
 }
@@ -3,6 +3,10 @@ module github.com/kirinyoku/twitch-kit
 go 1.24.1
 
 require (
-	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
+	github.com/joho/godotenv v1.5.1
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/sync v0.18.0
 )
+
+require golang.org/x/sys v0.29.0 // indirect
@@ -0,0 +1,119 @@
+package analytics
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// CommonFollow is a channel followed by both compared users.
+type CommonFollow struct {
+	User
+	FirstFollowedAt  time.Time // When the first user followed the channel
+	SecondFollowedAt time.Time // When the second user followed the channel
+}
+
+// Since returns when both users were following the channel.
+//
+// Returns:
+//
+//	The later of the two follow dates
+func (c CommonFollow) Since() time.Time {
+	if c.FirstFollowedAt.After(c.SecondFollowedAt) {
+		return c.FirstFollowedAt
+	}
+	return c.SecondFollowedAt
+}
+
+// FollowComparison describes how the follow lists of two users relate.
+type FollowComparison struct {
+	First      string           // Login of the first user
+	Second     string           // Login of the second user
+	Common     []CommonFollow   // Channels followed by both, earliest common follow first
+	OnlyFirst  []fetcher.Follow // Channels followed only by the first user
+	OnlySecond []fetcher.Follow // Channels followed only by the second user
+	Similarity float64          // Jaccard index: common channels divided by all followed channels
+}
+
+// EarliestCommon returns the channel both users have been following the longest.
+//
+// Returns:
+//
+//	The earliest common follow and false if the users follow no common channel
+func (c FollowComparison) EarliestCommon() (CommonFollow, bool) {
+	if len(c.Common) == 0 {
+		return CommonFollow{}, false
+	}
+	return c.Common[0], true
+}
+
+// CompareFollows compares the follow lists of two users.
+// Channels are matched by ID, falling back to the login when the ID is missing.
+//
+// Parameters:
+//
+//	first - Login of the first user
+//	firstFollows - Follow list of the first user
+//	second - Login of the second user
+//	secondFollows - Follow list of the second user
+//
+// Returns:
+//
+//	The comparison of the two lists
+func CompareFollows(first string, firstFollows []fetcher.Follow, second string, secondFollows []fetcher.Follow) FollowComparison {
+	comparison := FollowComparison{First: first, Second: second}
+
+	followedBySecond := make(map[string]fetcher.Follow, len(secondFollows))
+	for _, follow := range secondFollows {
+		followedBySecond[followKey(follow)] = follow
+	}
+
+	matched := make(map[string]bool, len(firstFollows))
+	for _, follow := range firstFollows {
+		k := followKey(follow)
+		if matched[k] {
+			continue
+		}
+		matched[k] = true
+
+		other, ok := followedBySecond[k]
+		if !ok {
+			comparison.OnlyFirst = append(comparison.OnlyFirst, follow)
+			continue
+		}
+
+		comparison.Common = append(comparison.Common, CommonFollow{
+			User:             User{ID: follow.ID, Login: follow.Login, DisplayName: follow.DisplayName},
+			FirstFollowedAt:  follow.FollowedAt,
+			SecondFollowedAt: other.FollowedAt,
+		})
+	}
+
+	seen := make(map[string]bool, len(secondFollows))
+	for _, follow := range secondFollows {
+		k := followKey(follow)
+		if matched[k] || seen[k] {
+			continue
+		}
+		seen[k] = true
+		comparison.OnlySecond = append(comparison.OnlySecond, follow)
+	}
+
+	slices.SortStableFunc(comparison.Common, func(a, b CommonFollow) int {
+		return a.Since().Compare(b.Since())
+	})
+
+	union := len(comparison.Common) + len(comparison.OnlyFirst) + len(comparison.OnlySecond)
+	if union > 0 {
+		comparison.Similarity = float64(len(comparison.Common)) / float64(union)
+	}
+
+	return comparison
+}
+
+// followKey returns the identity used to match followed channels.
+func followKey(follow fetcher.Follow) string {
+	return key(User{ID: follow.ID, Login: strings.ToLower(follow.Login)})
+}
@@ -0,0 +1,144 @@
+package analytics
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+func follow(id, login string, day int) fetcher.Follow {
+	return fetcher.Follow{ID: id, Login: login, DisplayName: login, FollowedAt: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// followLogins returns the logins of follows in order.
+func followLogins(follows []fetcher.Follow) []string {
+	out := make([]string, 0, len(follows))
+	for _, f := range follows {
+		out = append(out, f.Login)
+	}
+	return out
+}
+
+// commonLogins returns the logins of common follows in order.
+func commonLogins(common []CommonFollow) []string {
+	out := make([]string, 0, len(common))
+	for _, c := range common {
+		out = append(out, c.Login)
+	}
+	return out
+}
+
+func TestCompareFollows(t *testing.T) {
+	tests := []struct {
+		name           string
+		first, second  []fetcher.Follow
+		wantCommon     []string
+		wantOnlyFirst  []string
+		wantOnlySecond []string
+		wantSimilarity float64
+	}{
+		{
+			name:           "both lists empty",
+			wantCommon:     []string{},
+			wantOnlyFirst:  []string{},
+			wantOnlySecond: []string{},
+			wantSimilarity: 0,
+		},
+		{
+			name:           "one list empty",
+			first:          []fetcher.Follow{follow("1", "a", 1)},
+			wantCommon:     []string{},
+			wantOnlyFirst:  []string{"a"},
+			wantOnlySecond: []string{},
+			wantSimilarity: 0,
+		},
+		{
+			name:           "identical lists",
+			first:          []fetcher.Follow{follow("1", "a", 1), follow("2", "b", 2)},
+			second:         []fetcher.Follow{follow("2", "b", 3), follow("1", "a", 4)},
+			wantCommon:     []string{"b", "a"},
+			wantOnlyFirst:  []string{},
+			wantOnlySecond: []string{},
+			wantSimilarity: 1,
+		},
+		{
+			name:           "partial overlap",
+			first:          []fetcher.Follow{follow("1", "a", 1), follow("2", "b", 1)},
+			second:         []fetcher.Follow{follow("2", "b", 1), follow("3", "c", 1), follow("4", "d", 1)},
+			wantCommon:     []string{"b"},
+			wantOnlyFirst:  []string{"a"},
+			wantOnlySecond: []string{"c", "d"},
+			wantSimilarity: 0.25,
+		},
+		{
+			name:           "matched by ID despite a renamed login",
+			first:          []fetcher.Follow{follow("1", "old", 1)},
+			second:         []fetcher.Follow{follow("1", "new", 1)},
+			wantCommon:     []string{"old"},
+			wantOnlyFirst:  []string{},
+			wantOnlySecond: []string{},
+			wantSimilarity: 1,
+		},
+		{
+			name:           "matched by login without an ID, ignoring case",
+			first:          []fetcher.Follow{follow("", "Chan", 1)},
+			second:         []fetcher.Follow{follow("", "chan", 1)},
+			wantCommon:     []string{"Chan"},
+			wantOnlyFirst:  []string{},
+			wantOnlySecond: []string{},
+			wantSimilarity: 1,
+		},
+		{
+			name:           "duplicates are counted once",
+			first:          []fetcher.Follow{follow("1", "a", 1), follow("1", "a", 1), follow("2", "b", 1)},
+			second:         []fetcher.Follow{follow("1", "a", 1), follow("3", "c", 1), follow("3", "c", 1)},
+			wantCommon:     []string{"a"},
+			wantOnlyFirst:  []string{"b"},
+			wantOnlySecond: []string{"c"},
+			wantSimilarity: 1.0 / 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := CompareFollows("x", tt.first, "y", tt.second)
+
+			check := func(field string, got, want []string) {
+				t.Helper()
+				if !slices.Equal(got, want) {
+					t.Errorf("%s = %v, want %v", field, got, want)
+				}
+			}
+
+			check("Common", commonLogins(c.Common), tt.wantCommon)
+			check("OnlyFirst", followLogins(c.OnlyFirst), tt.wantOnlyFirst)
+			check("OnlySecond", followLogins(c.OnlySecond), tt.wantOnlySecond)
+
+			if c.Similarity != tt.wantSimilarity {
+				t.Errorf("Similarity = %v, want %v", c.Similarity, tt.wantSimilarity)
+			}
+		})
+	}
+}
+
+func TestEarliestCommon(t *testing.T) {
+	c := CompareFollows("x",
+		[]fetcher.Follow{follow("1", "a", 10), follow("2", "b", 1)},
+		"y",
+		[]fetcher.Follow{follow("1", "a", 2), follow("2", "b", 5)},
+	)
+
+	earliest, ok := c.EarliestCommon()
+	if !ok || earliest.Login != "b" {
+		t.Fatalf("EarliestCommon() = %q, %t, want b", earliest.Login, ok)
+	}
+	if want := follow("", "", 5).FollowedAt; !earliest.Since().Equal(want) {
+		t.Errorf("Since() = %v, want %v", earliest.Since(), want)
+	}
+
+	if _, ok := CompareFollows("x", nil, "y", nil).EarliestCommon(); ok {
+		t.Error("EarliestCommon() of empty lists reported a channel")
+	}
+}
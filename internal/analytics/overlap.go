@@ -0,0 +1,153 @@
+// Package analytics compares Twitch channel lists across channels and users.
+package analytics
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// Role is a privilege a user holds in a channel.
+type Role string
+
+const (
+	RoleMod Role = "mod"
+	RoleVip Role = "vip"
+)
+
+// User identifies a Twitch user.
+type User struct {
+	ID          string
+	Login       string
+	DisplayName string
+}
+
+// ChannelRoles holds the privileged users of one channel.
+type ChannelRoles struct {
+	Channel string        // Twitch channel name
+	Mods    []fetcher.Mod // Channel moderators, nil if not requested
+	Vips    []fetcher.Vip // Channel VIPs, nil if not requested
+}
+
+// Membership is a role a user holds in a specific channel.
+type Membership struct {
+	Channel string
+	Role    Role
+}
+
+// SharedUser is a user privileged in more than one channel.
+type SharedUser struct {
+	User
+	Memberships []Membership // Roles in input channel order
+}
+
+// Channels returns the number of distinct channels the user is privileged in.
+//
+// Returns:
+//
+//	The channel count
+func (u SharedUser) Channels() int {
+	count := 0
+	for i, m := range u.Memberships {
+		if i == 0 || u.Memberships[i-1].Channel != m.Channel {
+			count++
+		}
+	}
+	return count
+}
+
+// Overlap describes how the privileged users of several channels intersect.
+type Overlap struct {
+	Channels []string     // Compared channels in input order
+	Users    []SharedUser // Users privileged in at least two channels, most shared first
+	Matrix   [][]int      // Matrix[i][j] is the number of users privileged in both channels i and j; Matrix[i][i] is the size of channel i
+}
+
+// Overlaps computes the users shared between channels.
+// Users are matched by ID, falling back to the login when the ID is missing.
+//
+// Parameters:
+//
+//	channels - Mods and VIPs of each channel
+//
+// Returns:
+//
+//	The overlap of the channels
+func Overlaps(channels []ChannelRoles) Overlap {
+	type member struct {
+		user        User
+		memberships []Membership
+		in          []bool
+	}
+
+	members := make(map[string]*member)
+	var order []string
+
+	add := func(index int, channel string, user User, role Role) {
+		k := key(user)
+		m, ok := members[k]
+		if !ok {
+			m = &member{user: user, in: make([]bool, len(channels))}
+			members[k] = m
+			order = append(order, k)
+		}
+		if slices.Contains(m.memberships, Membership{Channel: channel, Role: role}) {
+			return
+		}
+		m.memberships = append(m.memberships, Membership{Channel: channel, Role: role})
+		m.in[index] = true
+	}
+
+	overlap := Overlap{Matrix: make([][]int, len(channels))}
+	for i, c := range channels {
+		overlap.Channels = append(overlap.Channels, c.Channel)
+		overlap.Matrix[i] = make([]int, len(channels))
+
+		for _, mod := range c.Mods {
+			add(i, c.Channel, User{ID: mod.ID, Login: mod.Login, DisplayName: mod.DisplayName}, RoleMod)
+		}
+		for _, vip := range c.Vips {
+			add(i, c.Channel, User{ID: vip.ID, Login: vip.Login, DisplayName: vip.DisplayName}, RoleVip)
+		}
+	}
+
+	for _, k := range order {
+		m := members[k]
+
+		shared := 0
+		for i := range channels {
+			if !m.in[i] {
+				continue
+			}
+			shared++
+			for j := range channels {
+				if m.in[j] {
+					overlap.Matrix[i][j]++
+				}
+			}
+		}
+
+		if shared > 1 {
+			overlap.Users = append(overlap.Users, SharedUser{User: m.user, Memberships: m.memberships})
+		}
+	}
+
+	slices.SortStableFunc(overlap.Users, func(a, b SharedUser) int {
+		if c := cmp.Compare(b.Channels(), a.Channels()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Login, b.Login)
+	})
+
+	return overlap
+}
+
+// key returns the identity used to match users across lists.
+func key(user User) string {
+	if user.ID != "" {
+		return "id:" + user.ID
+	}
+	return "login:" + strings.ToLower(user.Login)
+}
@@ -0,0 +1,148 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+func mod(id, login string) fetcher.Mod {
+	return fetcher.Mod{ID: id, Login: login, DisplayName: login}
+}
+
+func vip(id, login string) fetcher.Vip {
+	return fetcher.Vip{ID: id, Login: login, DisplayName: login}
+}
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name       string
+		channels   []ChannelRoles
+		wantUsers  map[string][]Membership // Memberships by login of every shared user
+		wantOrder  []string                // Logins of the shared users in order, nil to skip
+		wantMatrix [][]int
+	}{
+		{
+			name:       "no channels",
+			channels:   nil,
+			wantUsers:  map[string][]Membership{},
+			wantMatrix: [][]int{},
+		},
+		{
+			name: "no shared users",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("1", "alice")}},
+				{Channel: "b", Mods: []fetcher.Mod{mod("2", "bob")}},
+			},
+			wantUsers:  map[string][]Membership{},
+			wantMatrix: [][]int{{1, 0}, {0, 1}},
+		},
+		{
+			name: "user shared with different roles",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("1", "alice")}},
+				{Channel: "b", Vips: []fetcher.Vip{vip("1", "alice")}},
+			},
+			wantUsers: map[string][]Membership{
+				"alice": {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleVip}},
+			},
+			wantMatrix: [][]int{{1, 1}, {1, 1}},
+		},
+		{
+			name: "matched by ID despite a renamed login",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("1", "alice")}},
+				{Channel: "b", Mods: []fetcher.Mod{mod("1", "alice_renamed")}},
+			},
+			wantUsers: map[string][]Membership{
+				"alice": {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleMod}},
+			},
+			wantMatrix: [][]int{{1, 1}, {1, 1}},
+		},
+		{
+			name: "matched by login without an ID, ignoring case",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("", "Alice")}},
+				{Channel: "b", Vips: []fetcher.Vip{vip("", "alice")}},
+			},
+			wantUsers: map[string][]Membership{
+				"Alice": {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleVip}},
+			},
+			wantMatrix: [][]int{{1, 1}, {1, 1}},
+		},
+		{
+			name: "mod and VIP of one channel is not shared",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("1", "alice")}, Vips: []fetcher.Vip{vip("1", "alice")}},
+				{Channel: "b", Mods: []fetcher.Mod{mod("2", "bob")}},
+			},
+			wantUsers:  map[string][]Membership{},
+			wantMatrix: [][]int{{1, 0}, {0, 1}},
+		},
+		{
+			name: "duplicate roles are counted once",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("1", "alice"), mod("1", "alice")}},
+				{Channel: "b", Mods: []fetcher.Mod{mod("1", "alice")}},
+			},
+			wantUsers: map[string][]Membership{
+				"alice": {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleMod}},
+			},
+			wantMatrix: [][]int{{1, 1}, {1, 1}},
+		},
+		{
+			name: "most shared first, then by login",
+			channels: []ChannelRoles{
+				{Channel: "a", Mods: []fetcher.Mod{mod("3", "carol"), mod("2", "bob"), mod("1", "alice")}},
+				{Channel: "b", Mods: []fetcher.Mod{mod("3", "carol"), mod("2", "bob")}, Vips: []fetcher.Vip{vip("1", "alice")}},
+				{Channel: "c", Vips: []fetcher.Vip{vip("3", "carol")}},
+			},
+			wantUsers: map[string][]Membership{
+				"alice": {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleVip}},
+				"bob":   {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleMod}},
+				"carol": {{Channel: "a", Role: RoleMod}, {Channel: "b", Role: RoleMod}, {Channel: "c", Role: RoleVip}},
+			},
+			wantOrder:  []string{"carol", "alice", "bob"},
+			wantMatrix: [][]int{{3, 3, 1}, {3, 3, 1}, {1, 1, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlap := Overlaps(tt.channels)
+
+			users := make(map[string][]Membership)
+			var order []string
+			for _, u := range overlap.Users {
+				users[u.Login] = u.Memberships
+				order = append(order, u.Login)
+			}
+
+			if !reflect.DeepEqual(users, tt.wantUsers) {
+				t.Errorf("users = %v, want %v", users, tt.wantUsers)
+			}
+			if tt.wantOrder != nil && !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("order = %v, want %v", order, tt.wantOrder)
+			}
+			if !reflect.DeepEqual(overlap.Matrix, tt.wantMatrix) {
+				t.Errorf("matrix = %v, want %v", overlap.Matrix, tt.wantMatrix)
+			}
+			if len(overlap.Channels) != len(tt.channels) {
+				t.Errorf("channels = %v, want %d", overlap.Channels, len(tt.channels))
+			}
+		})
+	}
+}
+
+func TestSharedUserChannels(t *testing.T) {
+	user := SharedUser{Memberships: []Membership{
+		{Channel: "a", Role: RoleMod},
+		{Channel: "a", Role: RoleVip},
+		{Channel: "b", Role: RoleVip},
+	}}
+
+	if got := user.Channels(); got != 2 {
+		t.Errorf("Channels() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,319 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// AccessRule is an administrator's decision about a Telegram user.
+type AccessRule string
+
+const (
+	AccessAllow AccessRule = "allow" // The user is never rate limited
+	AccessDeny  AccessRule = "deny"  // The user is ignored by the bot
+)
+
+// AccessStore defines an interface for storing the allowlist and denylist.
+type AccessStore interface {
+	SetAccessRule(userID int64, rule AccessRule) error
+	DeleteAccessRule(userID int64) (bool, error)
+	AccessRule(userID int64) (AccessRule, bool, error)
+	AccessRules() (map[int64]AccessRule, error)
+}
+
+// AccessBackend defines an interface for persisting access rules by name.
+type AccessBackend interface {
+	SetAccessRule(userID int64, rule string) error
+	DeleteAccessRule(userID int64) (bool, error)
+	AccessRule(userID int64) (string, bool, error)
+	AccessRules() (map[int64]string, error)
+}
+
+// MemoryAccessStore keeps access rules in memory.
+type MemoryAccessStore struct {
+	mu    sync.Mutex
+	rules map[int64]AccessRule
+}
+
+// NewMemoryAccessStore creates an in-memory AccessStore.
+//
+// Returns:
+//
+//	A pointer to a new MemoryAccessStore instance
+func NewMemoryAccessStore() *MemoryAccessStore {
+	return &MemoryAccessStore{rules: make(map[int64]AccessRule)}
+}
+
+// SetAccessRule stores a user's access rule, replacing any existing one.
+func (s *MemoryAccessStore) SetAccessRule(userID int64, rule AccessRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rules[userID] = rule
+	return nil
+}
+
+// DeleteAccessRule removes a user's access rule.
+func (s *MemoryAccessStore) DeleteAccessRule(userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.rules[userID]
+	delete(s.rules, userID)
+	return ok, nil
+}
+
+// AccessRule returns a user's access rule.
+func (s *MemoryAccessStore) AccessRule(userID int64) (AccessRule, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.rules[userID]
+	return rule, ok, nil
+}
+
+// AccessRules returns every stored access rule.
+func (s *MemoryAccessStore) AccessRules() (map[int64]AccessRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := make(map[int64]AccessRule, len(s.rules))
+	for userID, rule := range s.rules {
+		rules[userID] = rule
+	}
+	return rules, nil
+}
+
+// PersistentAccessStore keeps access rules in an AccessBackend so the lists
+// survive restarts.
+type PersistentAccessStore struct {
+	backend AccessBackend
+}
+
+// NewPersistentAccessStore creates an AccessStore backed by persistent storage.
+//
+// Parameters:
+//
+//	backend - Storage for the rules
+//
+// Returns:
+//
+//	A pointer to a new PersistentAccessStore instance
+func NewPersistentAccessStore(backend AccessBackend) *PersistentAccessStore {
+	return &PersistentAccessStore{backend: backend}
+}
+
+// SetAccessRule stores a user's access rule, replacing any existing one.
+func (s *PersistentAccessStore) SetAccessRule(userID int64, rule AccessRule) error {
+	return s.backend.SetAccessRule(userID, string(rule))
+}
+
+// DeleteAccessRule removes a user's access rule.
+func (s *PersistentAccessStore) DeleteAccessRule(userID int64) (bool, error) {
+	return s.backend.DeleteAccessRule(userID)
+}
+
+// AccessRule returns a user's access rule.
+func (s *PersistentAccessStore) AccessRule(userID int64) (AccessRule, bool, error) {
+	rule, ok, err := s.backend.AccessRule(userID)
+	return AccessRule(rule), ok, err
+}
+
+// AccessRules returns every stored access rule.
+func (s *PersistentAccessStore) AccessRules() (map[int64]AccessRule, error) {
+	stored, err := s.backend.AccessRules()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[int64]AccessRule, len(stored))
+	for userID, rule := range stored {
+		rules[userID] = AccessRule(rule)
+	}
+	return rules, nil
+}
+
+// accessRule returns a user's access rule. Losing the access lists must not
+// take the bot down, so errors are logged and treated as no rule.
+func (b *Bot) accessRule(userID int64) (AccessRule, bool) {
+	if userID == 0 {
+		return "", false
+	}
+
+	rule, ok, err := b.access.AccessRule(userID)
+	if err != nil {
+		log.Printf("bot.accessRule: %v", err)
+		return "", false
+	}
+	return rule, ok
+}
+
+// admit decides whether an update is handled at all. Denylisted users are
+// ignored; everyone else, including unknown senders, is admitted.
+//
+// Parameters:
+//
+//	user - Sender of the update, nil if unknown
+//
+// Returns:
+//
+//	True if the update should be handled
+func (b *Bot) admit(user *tgbotapi.User) bool {
+	if user == nil || b.admins[user.ID] {
+		return true
+	}
+
+	rule, ok := b.accessRule(user.ID)
+	return !ok || rule != AccessDeny
+}
+
+// charge counts a request against the rate limits of its sender and chat.
+// Administrators and allowlisted users are never limited. A limited user is
+// told once per cooldown.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	chatID - Chat the request came from
+//	user - Sender of the request, nil if unknown
+//
+// Returns:
+//
+//	True if the request should be handled
+func (b *Bot) charge(ctx context.Context, chatID int64, user *tgbotapi.User) bool {
+	const op = "bot.charge"
+
+	if b.limiter == nil {
+		return true
+	}
+
+	var (
+		userID int64
+		lang   string
+	)
+	if user != nil {
+		userID, lang = user.ID, user.LanguageCode
+	}
+
+	if b.admins[userID] {
+		return true
+	}
+	if rule, ok := b.accessRule(userID); ok && rule == AccessAllow {
+		return true
+	}
+
+	verdict := b.limiter.allow(userID, chatID, time.Now())
+	if verdict.allowed {
+		return true
+	}
+
+	if verdict.notify {
+		log.Printf("%s: user %d in chat %d limited for %s", op, userID, chatID, formatWait(verdict.wait))
+
+		text := localize(lang, msgCooldown, formatWait(verdict.wait))
+		if verdict.quota {
+			text = localize(lang, msgQuotaExceeded, b.limiter.limits.DailyQuota, formatWait(verdict.wait))
+		}
+		if err := b.sendText(ctx, chatID, text); err != nil {
+			log.Printf("%s: %v", op, err)
+		}
+	}
+
+	return false
+}
+
+// ViewCmdAccess creates a view handler for the administrators' /access command.
+// It shows or changes the allowlist and denylist, e.g. "/access deny 123456".
+//
+// Returns:
+//
+//	A ViewFunc that handles the access command interaction
+func (b *Bot) ViewCmdAccess() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		if update.Message.From == nil || !b.admins[update.Message.From.ID] {
+			return b.sendText(ctx, chatID, localize(lang, msgAdminOnly))
+		}
+
+		args := strings.Fields(update.Message.CommandArguments())
+		if len(args) == 0 {
+			rules, err := b.access.AccessRules()
+			if err != nil {
+				return err
+			}
+			return b.sendText(ctx, chatID, localize(lang, msgAccessList,
+				formatAccessList(rules, AccessAllow), formatAccessList(rules, AccessDeny)))
+		}
+
+		if len(args) != 2 {
+			return b.sendText(ctx, chatID, localize(lang, msgAccessUsage))
+		}
+		userID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || userID <= 0 {
+			return b.sendText(ctx, chatID, localize(lang, msgAccessUsage))
+		}
+
+		switch strings.ToLower(args[0]) {
+		case "allow":
+			if err := b.access.SetAccessRule(userID, AccessAllow); err != nil {
+				return err
+			}
+			return b.sendText(ctx, chatID, localize(lang, msgAccessAllowed, userID))
+
+		case "deny":
+			if err := b.access.SetAccessRule(userID, AccessDeny); err != nil {
+				return err
+			}
+			return b.sendText(ctx, chatID, localize(lang, msgAccessDenied, userID))
+
+		case "remove":
+			removed, err := b.access.DeleteAccessRule(userID)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				return b.sendText(ctx, chatID, localize(lang, msgAccessNotListed, userID))
+			}
+			return b.sendText(ctx, chatID, localize(lang, msgAccessRemoved, userID))
+		}
+
+		return b.sendText(ctx, chatID, localize(lang, msgAccessUsage))
+	}
+}
+
+// formatAccessList renders the sorted IDs of the users with a rule, or "-" if there are none.
+func formatAccessList(rules map[int64]AccessRule, rule AccessRule) string {
+	var ids []int64
+	for userID, r := range rules {
+		if r == rule {
+			ids = append(ids, userID)
+		}
+	}
+	if len(ids) == 0 {
+		return "-"
+	}
+
+	slices.Sort(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatWait renders a cooldown compactly, e.g. "7s", "12m" or "5h32m".
+func formatWait(d time.Duration) string {
+	if d < time.Minute {
+		return max(d.Round(time.Second), time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
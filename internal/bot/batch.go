@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/query"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+const (
+	defaultBatchSize        = 10 // Default maximum number of channels in one lookup
+	defaultBatchConcurrency = 4  // Default number of channels fetched at the same time
+)
+
+// batchResult is the outcome of looking up one channel of a batch.
+type batchResult struct {
+	channel  string // Channel as entered or, when valid, its login
+	response string // Formatted list, empty on failure
+	err      error  // Validation or fetch error
+}
+
+// lookup fetches a list for one or more channels separated by commas, spaces or newlines.
+// A single channel gets the regular reply with a retry button; several channels are
+// fetched concurrently and reported together, with failures listed per channel.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chatID - Telegram chat ID to reply to
+//	lang - User's language code
+//	input - Channels entered by the user, optionally followed by query flags
+//	button - Selected option (e.g., "follows", "moders")
+//
+// Returns:
+//
+//	A *replyError if the input cannot be looked up at all
+func (b *Bot) lookup(ctx context.Context, chatID int64, lang, input, button string) error {
+	input, q, err := query.Parse(input, time.Now())
+	if err != nil {
+		return queryReplyError(err)
+	}
+	if err := q.Validate(listAliases[button]); err != nil {
+		return queryReplyError(err)
+	}
+
+	channels := utils.SplitUsernames(input)
+
+	switch {
+	case len(channels) == 0:
+		return &replyError{key: msgUsernameEmpty}
+
+	case len(channels) == 1:
+		channel, err := parseChannel(channels[0])
+		if err != nil {
+			return err
+		}
+		b.respond(ctx, chatID, lang, channel, button, q)
+		return nil
+
+	case len(channels) > b.batchSize:
+		return &replyError{key: msgBatchTooLarge, args: []any{b.batchSize}}
+	}
+
+	results := b.fetchBatch(ctx, channels, button, q)
+	b.sendBatchReport(ctx, chatID, lang, button, results)
+	return nil
+}
+
+// fetchBatch validates and fetches several channels with at most batchConcurrency requests in flight.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	channels - Channels entered by the user
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Sorting and filtering applied to every list
+//
+// Returns:
+//
+//	One result per channel, in input order
+func (b *Bot) fetchBatch(ctx context.Context, channels []string, button string, q query.Query) []batchResult {
+	results := make([]batchResult, len(channels))
+	valid := make([]int, 0, len(channels))
+
+	for i, input := range channels {
+		channel, err := parseChannel(input)
+		if err != nil {
+			results[i] = batchResult{channel: input, err: err}
+			continue
+		}
+		results[i] = batchResult{channel: channel}
+		valid = append(valid, i)
+	}
+
+	errs := b.fanOut(ctx, len(valid), b.batchConcurrency, func(ctx context.Context, n int) error {
+		result := &results[valid[n]]
+		var err error
+		result.response, err = b.processRequest(ctx, result.channel, button, q)
+		return err
+	})
+	for n, err := range errs {
+		results[valid[n]].err = err
+	}
+
+	return results
+}
+
+// fanOut calls fn for every index in [0, n) with at most limit calls running at once
+// and waits for all of them. Once ctx is done the remaining calls start without
+// waiting for a slot, so they fail fast instead of queueing. A call that panics
+// is reported and fails with an error instead of crashing the bot.
+//
+// Parameters:
+//
+//	ctx - Context passed to fn
+//	n - Number of calls
+//	limit - Maximum number of concurrent calls
+//	fn - Function called with each index
+//
+// Returns:
+//
+//	The error of each call, in index order
+func (b *Bot) fanOut(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) []error {
+	slots := make(chan struct{}, max(limit, 1))
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+			}
+
+			errs[i] = b.guard(ctx, fmt.Sprintf("fan-out call %d of %d", i+1, n), func() error {
+				return fn(ctx, i)
+			})
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// sendBatchReport sends the combined report of a batch lookup.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	chatID - Telegram chat ID to reply to
+//	lang - User's language code
+//	button - Selected option (e.g., "follows", "moders")
+//	results - Per-channel results
+func (b *Bot) sendBatchReport(ctx context.Context, chatID int64, lang, button string, results []batchResult) {
+	const op = "bot.sendBatchReport"
+
+	var (
+		report    strings.Builder
+		succeeded int
+	)
+
+	for _, result := range results {
+		if result.err != nil {
+			log.Printf("%s: %s %s: %v", op, button, result.channel, result.err)
+			text, _ := describeFetchError(lang, result.channel, button, result.err)
+			report.WriteString(formatFailure(result.channel, text) + "\n")
+			continue
+		}
+
+		succeeded++
+		report.WriteString(result.response)
+	}
+
+	summary := localize(lang, msgBatchSummary, succeeded, len(results))
+	b.sendPaged(ctx, chatID, 0, summary+"\n"+report.String(), nil)
+}
@@ -4,19 +4,23 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/kirinyoku/twitch-kit/internal/fetcher"
 	"github.com/kirinyoku/twitch-kit/internal/formatter"
-	"github.com/kirinyoku/twitch-kit/internal/utils"
+	"github.com/kirinyoku/twitch-kit/internal/query"
 )
 
 // UserState tracks the current state of a user's interaction with the bot.
 type UserState struct {
-	AwaitingUsername bool   // Indicates if the bot is waiting for a username input
-	PressedButton    string // Stores the button pressed by the user
+	Flow      string            // Name of the running flow
+	Step      string            // Current step of the flow
+	Data      map[string]string // Values collected by previous steps
+	ExpiresAt time.Time         // Deadline for answering the current step, zero for none
 }
 
 // Fetcher defines an interface for fetching Twitch channel data.
@@ -30,9 +34,35 @@ type Fetcher interface {
 // Bot manages Telegram bot operations and state.
 type Bot struct {
 	api        *tgbotapi.BotAPI    // Telegram Bot API instance
-	cmdViewMap map[string]ViewFunc // Maps commands to their view functions
-	userState  map[int64]UserState // Tracks user interaction states by chat ID
+	cmdViewMap map[string]ViewFunc // Maps commands and aliases to their view functions
+	unmetered  map[string]bool     // Commands and aliases exempt from the rate limits
+	commands   []command           // Registered commands in registration order
+	states     StateStore          // Tracks conversation states by chat and user
+	flows      *Machine            // Declared multi-step dialogs
+	pages      *pageStore          // Paginated results browsed with inline buttons
+	sender     *sender             // Rate-limited queue of outgoing messages
+	limiter    *limiter            // Rate limits of incoming requests, nil for unlimited
+	access     AccessStore         // Allowlist and denylist
+	admins     map[int64]bool      // Telegram user IDs of administrators
 	fetcher    Fetcher             // Interface for fetching Twitch data
+	pollFetch  Fetcher             // Fetcher of watchlist polls, bypassing caches
+	liveFetch  Fetcher             // Fetcher of go-live polls, bypassing caches
+	history    History             // Stored list snapshots, nil if change tracking is disabled
+	watcher    *watcher            // Watchlist scheduler, nil if watchlists are disabled
+	live       *liveNotifier       // Go-live notifier, nil if go-live notifications are disabled
+	webhook    *WebhookConfig      // Webhook settings, nil to receive updates through long polling
+	background sync.WaitGroup      // Tracks the watcher and go-live notifier
+	panics     atomic.Int64        // Number of panics recovered while handling updates
+	workers    int                 // Number of updates processed concurrently
+	queueSize  int                 // Maximum number of queued and in-flight updates
+
+	crashReporter CrashReporter // Notified of recovered panics, nil to only log them
+	sendLimits    SendLimits    // Rate limits of outgoing messages
+
+	shutdownTimeout time.Duration // Time limit for finishing in-flight work on shutdown
+
+	batchSize        int // Maximum number of channels in one lookup
+	batchConcurrency int // Number of channels of a lookup fetched at the same time
 }
 
 // ViewFunc defines a function type for handling bot view commands.
@@ -48,7 +78,16 @@ type Bot struct {
 //	An error if the operation fails
 type ViewFunc func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error
 
-const telegramMessageLimit = 4096 // Maximum length of a Telegram message
+const (
+	defaultWorkers       = 8                // Default number of concurrently processed updates
+	defaultQueueSize     = 256              // Default maximum number of queued updates
+	updateTimeout        = 5 * time.Second  // Time limit for handling a single update
+	replyTimeout         = 15 * time.Second // Additional time for sending the replies to an update
+	defaultShutdownTime  = 10 * time.Second // Default time limit for finishing in-flight work on shutdown
+	telegramMessageLimit = 4096             // Maximum length of a Telegram message
+	callbackDataLimit    = 64               // Maximum length of inline button callback data
+	retryCallbackPrefix  = "retry:"         // Callback data prefix of retry buttons
+)
 
 // New creates a new Bot instance with the provided API and fetcher.
 //
@@ -56,33 +95,80 @@ const telegramMessageLimit = 4096 // Maximum length of a Telegram message
 //
 //	api - Telegram Bot API instance
 //	fetcher - Implementation of the Fetcher interface
+//	opts - Optional features such as change history
 //
 // Returns:
 //
 //	A pointer to a new Bot instance
-func New(api *tgbotapi.BotAPI, fetcher Fetcher) *Bot {
-	return &Bot{
+func New(api *tgbotapi.BotAPI, fetcher Fetcher, opts ...Option) *Bot {
+	b := &Bot{
 		api:       api,
-		userState: make(map[int64]UserState),
+		states:    NewMemoryStateStore(defaultStateTTL),
+		access:    NewMemoryAccessStore(),
+		flows:     NewMachine(),
+		pages:     newPageStore(defaultPageTTL, defaultMaxPagedResults),
 		fetcher:   fetcher,
+		workers:   defaultWorkers,
+		queueSize: defaultQueueSize,
+
+		shutdownTimeout: defaultShutdownTime,
+		sendLimits:      defaultSendLimits,
+
+		batchSize:        defaultBatchSize,
+		batchConcurrency: defaultBatchConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt(b)
 	}
+
+	// Without a dedicated fetcher, watchlist and go-live polls share the cached one.
+	if b.pollFetch == nil {
+		b.pollFetch = b.fetcher
+	}
+	if b.liveFetch == nil {
+		b.liveFetch = b.pollFetch
+	}
+
+	b.sender = newSender(api, b.sendLimits)
+	b.registerFlows()
+
+	return b
 }
 
 // RegisterCommand associates a command with its view function.
+// Commands with a description are published to the Telegram command menu when the bot starts.
 //
 // Parameters:
 //
 //	name - Command name (e.g., "start")
 //	view - View function to handle the command
-func (b *Bot) RegisterCommand(name string, view ViewFunc) {
+//	opts - Optional description, aliases and rate limit exemption
+func (b *Bot) RegisterCommand(name string, view ViewFunc, opts ...CommandOption) {
 	if b.cmdViewMap == nil {
 		b.cmdViewMap = make(map[string]ViewFunc)
+		b.unmetered = make(map[string]bool)
+	}
+
+	cmd := command{name: name}
+	for _, opt := range opts {
+		opt(&cmd)
 	}
 
-	b.cmdViewMap[name] = view
+	for _, n := range append([]string{name}, cmd.aliases...) {
+		b.cmdViewMap[n] = view
+		b.unmetered[n] = cmd.unmetered
+	}
+	b.commands = append(b.commands, cmd)
 }
 
 // Start runs the bot and listens for updates until the context is done.
+// Updates are received through the webhook if one is configured, and through
+// long polling otherwise. They are processed concurrently, one at a time per chat.
+// When the context is done the bot stops receiving updates, queues the ones
+// already received and gives queued and in-flight updates and the background
+// pollers the shutdown timeout to finish,
+// so responses are not cut off and no storage write is pending afterwards.
 //
 // Parameters:
 //
@@ -90,27 +176,173 @@ func (b *Bot) RegisterCommand(name string, view ViewFunc) {
 //
 // Returns:
 //
-//	An error if the bot fails to start or stops unexpectedly
+//	Nil after a graceful shutdown, or an error if the bot fails to start,
+//	stops unexpectedly or does not finish its work within the shutdown timeout
 func (b *Bot) Start(ctx context.Context) error {
 	const op = "bot.Start"
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	if err := b.publishCommands(); err != nil {
+		log.Printf("%s: failed to publish commands: %v", op, err)
+	}
+
+	source, err := b.receiveUpdates()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	go b.sender.run()
+
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	defer stopPolling()
 
-	updates := b.api.GetUpdatesChan(u)
+	if b.watcher != nil {
+		b.background.Add(1)
+		go func() {
+			defer b.background.Done()
+			b.runWatcher(pollCtx)
+		}()
+	}
+
+	if b.live != nil {
+		b.background.Add(1)
+		go func() {
+			defer b.background.Done()
+			b.runLiveNotifier(pollCtx)
+		}()
+	}
+
+	// Handlers outlive ctx to finish in-flight updates, until Start returns.
+	handlerCtx, stopHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopHandlers()
+
+	d := newDispatcher(b.workers, b.queueSize, func(update tgbotapi.Update) {
+		// Replies get more time than the work itself, so a timeout can still be reported.
+		replyCtx, replyCancel := context.WithTimeout(handlerCtx, updateTimeout+replyTimeout)
+		defer replyCancel()
+		updateCtx, updateCancel := context.WithTimeout(replyCtx, updateTimeout)
+		defer updateCancel()
+		b.handleUpdate(context.WithValue(updateCtx, replyContextKey{}, replyCtx), update)
+	})
+	d.start()
+
+	// Updates received while the queue was full when the context ended.
+	var unqueued []tgbotapi.Update
 
 	for {
 		select {
-		case update := <-updates:
-			updateCtx, updateCancel := context.WithTimeout(ctx, 5*time.Second)
-			b.handleUpdate(updateCtx, update)
-			updateCancel()
+		case update := <-source.updates:
+			if !d.submit(ctx, update) {
+				unqueued = append(unqueued, update)
+			}
+		case err := <-source.errs:
+			source.stop()
+			b.drainUpdates(source, d, unqueued)
+			stopPolling()
+			if shutdownErr := b.shutdown(d); shutdownErr != nil {
+				log.Printf("%s: %v", op, shutdownErr)
+			}
+			return fmt.Errorf("%s: %w", op, err)
 		case <-ctx.Done():
-			return fmt.Errorf("%s: context done", op)
+			source.stop()
+			b.drainUpdates(source, d, unqueued)
+			if err := b.shutdown(d); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			return nil
 		}
 	}
 }
 
+// drainUpdates queues the updates a stopped source has already received.
+// Long polling confirms updates to Telegram as soon as it requests the next
+// batch, so updates left in the source's buffer would never be delivered again.
+// Updates that arrive after the buffer is empty were not confirmed and are
+// redelivered on the next start.
+//
+// Parameters:
+//
+//	source - Stopped update source
+//	d - Dispatcher to queue the updates on
+//	unqueued - Received updates that could not be queued yet
+func (b *Bot) drainUpdates(source updateSource, d *dispatcher, unqueued []tgbotapi.Update) {
+	const op = "bot.drainUpdates"
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.shutdownTimeout)
+	defer cancel()
+
+	queue := func(update tgbotapi.Update) {
+		if !d.submit(ctx, update) {
+			log.Printf("%s: queue still full, dropped update %d", op, update.UpdateID)
+		}
+	}
+
+	for _, update := range unqueued {
+		queue(update)
+	}
+
+	for {
+		select {
+		case update, ok := <-source.updates:
+			if !ok {
+				return
+			}
+			queue(update)
+		default:
+			return
+		}
+	}
+}
+
+// replyContextKey is the context key of the context replies to an update are sent with.
+type replyContextKey struct{}
+
+// replyContext returns the context replies to the update handled with ctx are
+// sent with, which ends later than ctx itself, or ctx outside update handling.
+func replyContext(ctx context.Context) context.Context {
+	if replyCtx, ok := ctx.Value(replyContextKey{}).(context.Context); ok {
+		return replyCtx
+	}
+	return ctx
+}
+
+// shutdown waits for queued and in-flight updates, the background pollers and
+// the outgoing message queue to finish, sharing a single shutdownTimeout
+// deadline. It must be called after updates stopped arriving and the pollers
+// were told to stop.
+//
+// Parameters:
+//
+//	d - Dispatcher of the stopped update source
+//
+// Returns:
+//
+//	An error if the work was not finished before the deadline
+func (b *Bot) shutdown(d *dispatcher) error {
+	deadline := time.Now().Add(b.shutdownTimeout)
+
+	if !d.drain(b.shutdownTimeout) {
+		return fmt.Errorf("queued updates not finished within %s", b.shutdownTimeout)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Until(deadline)):
+		return fmt.Errorf("background pollers not finished within %s", b.shutdownTimeout)
+	}
+
+	if !b.sender.close(time.Until(deadline)) {
+		return fmt.Errorf("outgoing messages not sent within %s", b.shutdownTimeout)
+	}
+
+	return nil
+}
+
 // handleUpdate processes incoming Telegram updates.
 //
 // Parameters:
@@ -122,10 +354,13 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 
 	defer func() {
 		if p := recover(); p != nil {
-			log.Fatalf("panic recovered: %v\n%s", p, string(debug.Stack()))
+			b.recoverUpdate(ctx, update, p)
 		}
 	}()
 
+	// Lets panics on other goroutines, such as fan-out workers, be reported with the update.
+	ctx = context.WithValue(ctx, updateContextKey{}, update)
+
 	if update.CallbackQuery != nil {
 		b.handleCallback(ctx, update.CallbackQuery)
 		return
@@ -135,16 +370,34 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 		return
 	}
 
-	if b.isAwaitingUsername(update.Message.From.ID) {
-		b.handleUserInput(ctx, update)
+	// Denylisted users are ignored before any command or flow handler runs.
+	if !b.admit(update.Message.From) {
 		return
 	}
 
+	chatID := update.Message.Chat.ID
+	key := messageStateKey(update.Message)
+
 	if update.Message.IsCommand() {
+		if b.metered(update.Message.Command()) && !b.charge(ctx, chatID, update.Message.From) {
+			return
+		}
+		if err := b.states.Delete(key); err != nil {
+			log.Printf("%s: failed to cancel flow: %v", op, err)
+		}
 		b.handleCommand(ctx, update)
-	} else {
-		b.sendStartKeyboard(ctx, update)
+		return
+	}
+
+	if state, ok := b.pendingState(key); ok {
+		if !b.charge(ctx, chatID, update.Message.From) {
+			return
+		}
+		b.handleUserInput(ctx, update, state)
+		return
 	}
+
+	b.sendStartKeyboard(ctx, update)
 }
 
 // handleCallback processes inline keyboard button presses.
@@ -161,13 +414,69 @@ func (b *Bot) handleCallback(ctx context.Context, callback *tgbotapi.CallbackQue
 		log.Printf("%s: failed to send callback: %v", op, err)
 	}
 
-	b.userState[callback.Message.Chat.ID] = UserState{
-		AwaitingUsername: true,
-		PressedButton:    callback.Data,
+	// Buttons of inline-mode messages carry no message to reply to.
+	if callback.Message == nil {
+		return
+	}
+
+	if !b.admit(callback.From) {
+		return
+	}
+
+	// Browsing an already fetched result is not rate limited.
+	if !strings.HasPrefix(callback.Data, pageCallbackPrefix) && !b.charge(ctx, callback.Message.Chat.ID, callback.From) {
+		return
+	}
+
+	chat := tgbotapi.Update{Message: callback.Message}
+	conv := Conversation{Key: callbackStateKey(callback), Lang: callback.From.LanguageCode}
+
+	if data, ok := strings.CutPrefix(callback.Data, retryCallbackPrefix); ok {
+		button, username, q := parseListCallback(data)
+		b.respond(ctx, callback.Message.Chat.ID, callback.From.LanguageCode, username, button, q)
+		b.sendStartKeyboard(ctx, chat)
+		return
+	}
+
+	if data, ok := strings.CutPrefix(callback.Data, queryCallbackPrefix); ok {
+		button, username, q := parseListCallback(data)
+		b.respondInPlace(ctx, callback.Message.Chat.ID, callback.Message.MessageID, callback.From.LanguageCode, username, button, q)
+		return
+	}
+
+	if data, ok := strings.CutPrefix(callback.Data, exportCallbackPrefix); ok {
+		b.handleExportCallback(ctx, callback, data)
+		return
+	}
+
+	if data, ok := strings.CutPrefix(callback.Data, pageCallbackPrefix); ok {
+		b.handlePageCallback(ctx, callback, data)
+		return
+	}
+
+	if value, ok := strings.CutPrefix(callback.Data, flowCallbackPrefix); ok {
+		state, pending := b.pendingState(conv.Key)
+		if !pending {
+			b.sendReply(ctx, conv.Key.ChatID, Reply{Text: localize(conv.Lang, msgFlowExpired)})
+			return
+		}
+		b.feedFlow(ctx, chat, conv, state, Input{Kind: InputCallback, Value: value})
+		return
+	}
+
+	if callback.Data == flowChanges {
+		b.startFlow(ctx, chat, conv, flowChanges, nil)
+		return
 	}
 
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, "Enter the channel name:")
-	b.api.Send(msg)
+	b.startFlow(ctx, chat, conv, flowLookup, map[string]string{"list": callback.Data})
+}
+
+// metered reports whether a command counts against the rate limits.
+// Unknown commands only get a hint and are not counted.
+func (b *Bot) metered(cmd string) bool {
+	_, ok := b.cmdViewMap[cmd]
+	return ok && !b.unmetered[cmd]
 }
 
 // handleCommand executes the appropriate view function for a command.
@@ -182,8 +491,9 @@ func (b *Bot) handleCommand(ctx context.Context, update tgbotapi.Update) {
 	cmd := update.Message.Command()
 	cmdView, ok := b.cmdViewMap[cmd]
 	if !ok {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Unknown command.")
-		b.api.Send(msg)
+		if err := b.sendText(ctx, update.Message.Chat.ID, "Unknown command."); err != nil {
+			log.Printf("%s: %v", op, err)
+		}
 		b.sendStartKeyboard(ctx, update)
 		return
 	}
@@ -193,107 +503,170 @@ func (b *Bot) handleCommand(ctx context.Context, update tgbotapi.Update) {
 	}
 }
 
-// handleUserInput processes username input from the user.
+// handleUserInput passes a text message to the user's running flow.
 //
 // Parameters:
 //
 //	ctx - Context for the operation
 //	update - Telegram update containing the user input
-func (b *Bot) handleUserInput(ctx context.Context, update tgbotapi.Update) {
-	const op = "bot.handleUserInput"
+//	state - Pending conversation state of the user
+func (b *Bot) handleUserInput(ctx context.Context, update tgbotapi.Update, state UserState) {
+	conv := Conversation{Key: messageStateKey(update.Message), Lang: messageLang(update.Message)}
+	b.feedFlow(ctx, update, conv, state, Input{Kind: InputText, Value: update.Message.Text})
+}
 
-	username := update.Message.Text
-	state := b.userState[update.Message.From.ID]
+// respond fetches the requested list and sends it, or a friendly error, to the chat.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chatID - Telegram chat ID to reply to
+//	lang - User's language code for error messages
+//	username - Twitch username to fetch data for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Sorting and filtering applied to the list
+func (b *Bot) respond(ctx context.Context, chatID int64, lang, username, button string, q query.Query) {
+	b.respondInPlace(ctx, chatID, 0, lang, username, button, q)
+}
 
-	delete(b.userState, update.Message.Chat.ID)
+// respondInPlace fetches the requested list and shows it in an existing message,
+// e.g. after a sort or filter button was pressed. Long lists are paginated.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chatID - Telegram chat ID to reply to
+//	messageID - Message to replace, zero to send a new one
+//	lang - User's language code for error messages
+//	username - Twitch username to fetch data for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Sorting and filtering applied to the list
+func (b *Bot) respondInPlace(ctx context.Context, chatID int64, messageID int, lang, username, button string, q query.Query) {
+	const op = "bot.respond"
 
-	response, err := b.processRequest(ctx, username, state.PressedButton)
+	response, err := b.processRequest(ctx, username, button, q)
 	if err != nil {
-		b.sendError(update.Message.Chat.ID, "Failed to fetch data", err)
-		b.sendStartKeyboard(ctx, update)
+		log.Printf("%s: %s %s: %v", op, button, username, err)
+		b.sendFetchError(ctx, chatID, lang, username, button, q, err)
 		return
 	}
 
-	messages := utils.SplitMessage(response, telegramMessageLimit)
-	for _, part := range messages {
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, part)
-		msg.ParseMode = tgbotapi.ModeHTML
-		msg.DisableWebPagePreview = true
-		b.api.Send(msg)
+	var extra [][]tgbotapi.InlineKeyboardButton
+	if keyboard, ok := queryKeyboard(lang, username, button, q); ok {
+		extra = keyboard.InlineKeyboard
+	}
+	if row := exportRow(lang, username, button, q); len(row) > 0 {
+		extra = append(extra, row)
 	}
 
-	b.sendStartKeyboard(ctx, update)
+	b.sendPaged(ctx, chatID, messageID, response, extra)
 }
 
-// isAwaitingUsername checks if the bot is waiting for a username from a user.
+// pendingState returns the conversation state if a flow is waiting for input from a user.
 //
 // Parameters:
 //
-//	chatID - Telegram chat ID of the user
+//	key - Conversation key of the user
 //
 // Returns:
 //
-//	True if awaiting username, false otherwise
-func (b *Bot) isAwaitingUsername(chatID int64) bool {
-	state, ok := b.userState[chatID]
-	return ok && state.AwaitingUsername
+//	The pending state and true if awaiting input, false otherwise
+func (b *Bot) pendingState(key StateKey) (UserState, bool) {
+	state, ok, err := b.states.Get(key)
+	if err != nil {
+		log.Printf("bot.pendingState: %v", err)
+		return UserState{}, false
+	}
+	return state, ok && state.Flow != ""
 }
 
-// processRequest fetches and formats data based on the user's selection.
+// processRequest fetches, sorts, filters and formats data based on the user's selection.
 //
 // Parameters:
 //
 //	ctx - Context for the operation
 //	username - Twitch username to fetch data for
 //	button - Selected option (e.g., "follows", "moders")
+//	q - Sorting and filtering applied to the list
 //
 // Returns:
 //
 //	Formatted response string and an error if any
-func (b *Bot) processRequest(ctx context.Context, username, button string) (string, error) {
+func (b *Bot) processRequest(ctx context.Context, username, button string, q query.Query) (string, error) {
+	if err := q.Validate(listAliases[button]); err != nil {
+		return "", queryReplyError(err)
+	}
+
 	switch button {
 	case "follows":
 		follows, err := b.fetcher.FetchFollows(ctx, username)
 		if err != nil {
 			return "", err
 		}
-		return formatter.FormatFollows(username, follows), nil
+		return formatQueried(username, query.Follows(follows, q), q, formatter.FormatFollows)
 
 	case "moders":
 		mods, err := b.fetcher.FetchMods(ctx, username)
 		if err != nil {
 			return "", err
 		}
-		return formatter.FormatMods(username, mods), nil
+		return formatQueried(username, query.Mods(mods, q), q, formatter.FormatMods)
 
 	case "vips":
 		vips, err := b.fetcher.FetchVips(ctx, username)
 		if err != nil {
 			return "", err
 		}
-		return formatter.FormatVips(username, vips), nil
+		return formatQueried(username, query.Vips(vips, q), q, formatter.FormatVips)
 
 	case "founders":
 		founders, err := b.fetcher.FetchFounders(ctx, username)
 		if err != nil {
 			return "", err
 		}
-		return formatter.FormatFounders(username, founders), nil
+		return formatQueried(username, query.Founders(founders, q), q, formatter.FormatFounders)
+
+	case "changes":
+		return b.processChanges(ctx, username)
 	}
 
 	return "", fmt.Errorf("unknown button: %s", button)
 }
 
-// sendError sends an error message to the user.
+// formatQueried formats a queried list, reporting when the query matched nothing.
+func formatQueried[T any](username string, items []T, q query.Query, format func(string, []T) string) (string, error) {
+	if len(items) == 0 && !q.IsZero() {
+		return "", &replyError{key: msgNoMatches, args: []any{username}}
+	}
+	return format(username, items), nil
+}
+
+// sendFetchError sends a localized description of a fetch error to the user,
+// with a retry button when the failure is transient.
 //
 // Parameters:
 //
+//	ctx - Context that aborts sending
 //	chatID - Telegram chat ID of the user
-//	message - Error message text
-//	err - Error details to include
-func (b *Bot) sendError(chatID int64, message string, err error) {
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("%s: %v.", message, err))
-	if _, err := b.api.Send(msg); err != nil {
+//	lang - User's language code
+//	username - Twitch username the request was made for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Sorting and filtering repeated by the retry button
+//	err - Error returned while fetching
+func (b *Bot) sendFetchError(ctx context.Context, chatID int64, lang, username, button string, q query.Query, err error) {
+	text, retryable := describeFetchError(lang, username, button, err)
+	msg := tgbotapi.NewMessage(chatID, text)
+
+	data := retryCallbackPrefix + listCallbackData(username, button, q)
+	if retryable && len(data) <= callbackDataLimit {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(localize(lang, msgRetryButton), data),
+			),
+		)
+	}
+
+	if _, err := b.send(ctx, chatID, msg); err != nil {
 		log.Printf("bot.handleUpdate: failed to send error message: %v", err)
 	}
 }
@@ -305,7 +678,7 @@ func (b *Bot) sendError(chatID int64, message string, err error) {
 //	ctx - Context for the operation
 //	update - Telegram update to respond to
 func (b *Bot) sendStartKeyboard(ctx context.Context, update tgbotapi.Update) {
-	view := ViewCmdStart()
+	view := b.ViewCmdStart()
 	if err := view(ctx, b.api, update); err != nil {
 		log.Printf("bot.handleUpdate: failed to send inline keyboard: %v", err)
 	}
@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/diff"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/formatter"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+// History defines an interface for looking up stored list snapshots.
+type History interface {
+	At(channel string, list fetcher.Endpoint, t time.Time) (storage.Snapshot, bool, error)
+	Oldest(channel string, list fetcher.Endpoint) (storage.Snapshot, bool, error)
+}
+
+// defaultChangesPeriod is used when the user does not specify a period.
+const defaultChangesPeriod = 7 * 24 * time.Hour
+
+// listAliases maps user-typed list names to endpoints.
+var listAliases = map[string]fetcher.Endpoint{
+	"follows":  fetcher.EndpointFollows,
+	"mods":     fetcher.EndpointMods,
+	"moders":   fetcher.EndpointMods,
+	"vips":     fetcher.EndpointVips,
+	"founders": fetcher.EndpointFounders,
+}
+
+// processChanges compares a channel's current list with its stored snapshot
+// from the start of the requested period.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	input - User input in the form "<channel> [list] [period]", e.g. "xqc mods 7d"
+//
+// Returns:
+//
+//	Formatted diff string and an error if any
+func (b *Bot) processChanges(ctx context.Context, input string) (string, error) {
+	if b.history == nil {
+		return "", &replyError{key: msgHistoryDisabled}
+	}
+
+	channel, list, period, err := parseChangesInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	since := time.Now().Add(-period)
+
+	var result diff.Result
+	switch list {
+	case fetcher.EndpointFollows:
+		result, err = changesSince(ctx, b.history, channel, list, since, b.fetcher.FetchFollows, diff.Follows)
+	case fetcher.EndpointMods:
+		result, err = changesSince(ctx, b.history, channel, list, since, b.fetcher.FetchMods, diff.Mods)
+	case fetcher.EndpointVips:
+		result, err = changesSince(ctx, b.history, channel, list, since, b.fetcher.FetchVips, diff.Vips)
+	case fetcher.EndpointFounders:
+		result, err = changesSince(ctx, b.history, channel, list, since, b.fetcher.FetchFounders, diff.Founders)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return formatter.FormatDiff(result), nil
+}
+
+// changesSince fetches the current list and diffs it against the newest snapshot taken at or before since.
+// If recording started after since, the oldest snapshot is used instead and the result says so.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	history - Snapshot history
+//	channel - Twitch channel name
+//	list - List type to compare
+//	since - Start of the compared period
+//	fetch - Fetch function for the current list
+//	compare - Diff function for the list type
+//
+// Returns:
+//
+//	The diff result and an error if any
+func changesSince[T any](ctx context.Context, history History, channel string, list fetcher.Endpoint, since time.Time, fetch func(context.Context, string) ([]T, error), compare func(older, newer []T) diff.Result) (diff.Result, error) {
+	snap, ok, err := history.At(channel, list, since)
+	if err != nil {
+		return diff.Result{}, err
+	}
+	if !ok {
+		snap, ok, err = history.Oldest(channel, list)
+		if err != nil {
+			return diff.Result{}, err
+		}
+	}
+	if !ok {
+		return diff.Result{}, &replyError{key: msgNoHistory, args: []any{channel}}
+	}
+
+	older, err := storage.Decode[T](snap)
+	if err != nil {
+		return diff.Result{}, err
+	}
+
+	current, err := fetch(ctx, channel)
+	if err != nil && !errors.Is(err, fetcher.ErrEmptyList) {
+		return diff.Result{}, err
+	}
+
+	result := compare(older, current)
+	result.Channel = channel
+	result.List = list
+	result.Since = since
+	result.From = snap.TakenAt
+	result.To = time.Now()
+
+	return result, nil
+}
+
+// parseChangesInput parses "<channel> [list] [period]" with list and period in any order.
+//
+// Parameters:
+//
+//	input - Raw user input
+//
+// Returns:
+//
+//	The channel, list type, period and an error if the input is invalid
+func parseChangesInput(input string) (string, fetcher.Endpoint, time.Duration, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || len(fields) > 3 {
+		return "", "", 0, &replyError{key: msgInvalidChanges, args: []any{input}}
+	}
+
+	channel, err := parseChannel(fields[0])
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	list := fetcher.EndpointMods
+	period := defaultChangesPeriod
+
+	for _, field := range fields[1:] {
+		if endpoint, ok := listAliases[strings.ToLower(field)]; ok {
+			list = endpoint
+			continue
+		}
+
+		d, err := utils.ParsePeriod(field)
+		if err != nil {
+			return "", "", 0, &replyError{key: msgInvalidChanges, args: []any{input}}
+		}
+		period = d
+	}
+
+	return channel, list, period, nil
+}
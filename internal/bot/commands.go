@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// commandNamePattern matches command names accepted by Telegram's setMyCommands.
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// command describes a registered command for the Telegram command menu.
+type command struct {
+	name        string   // Command name without the leading slash
+	description string   // Menu description, empty to keep the command out of the menu
+	aliases     []string // Alternative names handled by the same view
+	unmetered   bool     // Whether the command is exempt from the rate limits
+}
+
+// CommandOption configures a registered command.
+type CommandOption func(*command)
+
+// WithDescription publishes the command in the Telegram command menu.
+//
+// Parameters:
+//
+//	description - Text shown next to the command, 1-256 characters
+//
+// Returns:
+//
+//	A CommandOption that sets the description
+func WithDescription(description string) CommandOption {
+	return func(c *command) {
+		c.description = description
+	}
+}
+
+// WithAliases registers alternative names for the command. Aliases are
+// handled like the command itself but are not listed in the menu.
+//
+// Parameters:
+//
+//	aliases - Alternative command names
+//
+// Returns:
+//
+//	A CommandOption that adds the aliases
+func WithAliases(aliases ...string) CommandOption {
+	return func(c *command) {
+		c.aliases = append(c.aliases, aliases...)
+	}
+}
+
+// WithoutRateLimit exempts the command from the rate limits, e.g. for menus
+// and administration that never fetch from Twitch.
+//
+// Returns:
+//
+//	A CommandOption that exempts the command
+func WithoutRateLimit() CommandOption {
+	return func(c *command) {
+		c.unmetered = true
+	}
+}
+
+// publishCommands sends the described commands to Telegram via setMyCommands.
+//
+// Returns:
+//
+//	An error if a command is invalid or the request fails
+func (b *Bot) publishCommands() error {
+	const op = "bot.publishCommands"
+
+	var menu []tgbotapi.BotCommand
+	for _, cmd := range b.commands {
+		if cmd.description == "" {
+			continue
+		}
+		if !commandNamePattern.MatchString(cmd.name) {
+			return fmt.Errorf("%s: invalid command name %q", op, cmd.name)
+		}
+		menu = append(menu, tgbotapi.BotCommand{Command: cmd.name, Description: cmd.description})
+	}
+
+	if len(menu) == 0 {
+		return nil
+	}
+
+	if _, err := b.api.Request(tgbotapi.NewSetMyCommands(menu...)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// commandArgs splits a command's arguments into fields.
+//
+// Parameters:
+//
+//	update - Update containing the command
+//
+// Returns:
+//
+//	The whitespace-separated arguments after the command
+func commandArgs(update tgbotapi.Update) []string {
+	return strings.Fields(update.Message.CommandArguments())
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dispatcher processes updates on a pool of workers. Updates of the same chat
+// are handled one at a time in arrival order, while different chats proceed
+// concurrently.
+type dispatcher struct {
+	handle  func(update tgbotapi.Update) // Update handler
+	workers int                          // Number of worker goroutines
+	slots   chan struct{}                // Bounds the number of queued and in-flight updates
+	ready   chan int64                   // Chats with queued updates and no active worker
+	quit    chan struct{}                // Closed to stop the workers
+	mu      sync.Mutex                   // Guards chats
+	chats   map[int64][]tgbotapi.Update  // Queued updates per active chat
+	pending sync.WaitGroup               // Tracks queued and in-flight updates
+	stopped sync.WaitGroup               // Tracks running workers
+}
+
+// newDispatcher creates a dispatcher with the given pool and queue sizes.
+//
+// Parameters:
+//
+//	workers - Number of updates processed concurrently
+//	queueSize - Maximum number of queued and in-flight updates
+//	handle - Update handler
+//
+// Returns:
+//
+//	A pointer to a new dispatcher
+func newDispatcher(workers, queueSize int, handle func(update tgbotapi.Update)) *dispatcher {
+	return &dispatcher{
+		handle:  handle,
+		workers: workers,
+		slots:   make(chan struct{}, queueSize),
+		ready:   make(chan int64, queueSize),
+		quit:    make(chan struct{}),
+		chats:   make(map[int64][]tgbotapi.Update),
+	}
+}
+
+// start launches the worker goroutines.
+func (d *dispatcher) start() {
+	for range d.workers {
+		d.stopped.Add(1)
+		go d.work()
+	}
+}
+
+// submit queues an update, blocking while the queue is full.
+//
+// Parameters:
+//
+//	ctx - Context that aborts waiting for a free slot
+//	update - Update to queue
+//
+// Returns:
+//
+//	True if the update was queued, false if the context ended first
+func (d *dispatcher) submit(ctx context.Context, update tgbotapi.Update) bool {
+	const op = "bot.dispatcher.submit"
+
+	select {
+	case d.slots <- struct{}{}:
+	default:
+		log.Printf("%s: queue full (%d updates), waiting for a free slot", op, cap(d.slots))
+		start := time.Now()
+
+		select {
+		case d.slots <- struct{}{}:
+			log.Printf("%s: queue slot freed after %s", op, time.Since(start))
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	chatID := updateChatID(update)
+	d.pending.Add(1)
+
+	d.mu.Lock()
+	queue, active := d.chats[chatID]
+	d.chats[chatID] = append(queue, update)
+	d.mu.Unlock()
+
+	if !active {
+		d.ready <- chatID
+	}
+
+	return true
+}
+
+// work handles one update of a ready chat at a time and requeues the chat
+// while it still has updates, so a busy chat cannot starve the others.
+func (d *dispatcher) work() {
+	defer d.stopped.Done()
+
+	for {
+		select {
+		case chatID := <-d.ready:
+			d.mu.Lock()
+			update := d.chats[chatID][0]
+			d.mu.Unlock()
+
+			d.handle(update)
+
+			d.mu.Lock()
+			queue := d.chats[chatID][1:]
+			if len(queue) == 0 {
+				delete(d.chats, chatID)
+			} else {
+				d.chats[chatID] = queue
+			}
+			d.mu.Unlock()
+
+			<-d.slots
+			d.pending.Done()
+
+			if len(queue) > 0 {
+				d.ready <- chatID
+			}
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// drain waits until all queued and in-flight updates are handled or the timeout expires,
+// then stops the workers. No updates may be submitted once drain is called.
+//
+// Parameters:
+//
+//	timeout - Maximum time to wait for queued updates
+//
+// Returns:
+//
+//	True if every update was handled before the timeout
+func (d *dispatcher) drain(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		d.pending.Wait()
+		close(done)
+	}()
+
+	drained := true
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		drained = false
+	}
+
+	close(d.quit)
+	if drained {
+		d.stopped.Wait()
+	}
+
+	return drained
+}
+
+// updateChatID returns the chat an update belongs to, or zero if it has none.
+func updateChatID(update tgbotapi.Update) int64 {
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	return 0
+}
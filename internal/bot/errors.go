@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+// emptyListMessages maps a selected option to the message shown when its list is empty.
+var emptyListMessages = map[string]messageKey{
+	"follows":  msgEmptyFollows,
+	"moders":   msgEmptyMods,
+	"vips":     msgEmptyVips,
+	"founders": msgEmptyFounders,
+}
+
+// replyError is an error whose user-facing reply is known when it is created,
+// such as invalid input or a disabled feature.
+type replyError struct {
+	key  messageKey // Message to reply with
+	args []any      // Format arguments of the message
+}
+
+// Error implements the error interface using the default language.
+func (e *replyError) Error() string {
+	return fmt.Sprintf(catalog[defaultLanguage][e.key], e.args...)
+}
+
+// describeFetchError converts a fetcher error into a friendly, localized reply.
+//
+// Parameters:
+//
+//	lang - User's language code
+//	username - Twitch username the request was made for
+//	button - Selected option (e.g., "follows", "moders")
+//	err - Error returned by the fetcher
+//
+// Returns:
+//
+//	The reply text and whether offering a retry makes sense
+func describeFetchError(lang, username, button string, err error) (string, bool) {
+	var replyErr *replyError
+	if errors.As(err, &replyErr) {
+		return localize(lang, replyErr.key, replyErr.args...), false
+	}
+
+	switch {
+	case errors.Is(err, fetcher.ErrUserNotFound):
+		return localize(lang, msgUserNotFound, username), false
+	case errors.Is(err, fetcher.ErrEmptyList):
+		key, ok := emptyListMessages[button]
+		if !ok {
+			key = msgEmptyList
+		}
+		return localize(lang, key, username), false
+	case errors.Is(err, fetcher.ErrRateLimited):
+		return localize(lang, msgRateLimited), true
+	case errors.Is(err, fetcher.ErrUpstreamUnavailable):
+		return localize(lang, msgUnavailable), true
+	case errors.Is(err, context.DeadlineExceeded):
+		return localize(lang, msgTimeout), true
+	case errors.Is(err, errPanicked):
+		return localize(lang, msgInternalError), false
+	default:
+		return localize(lang, msgFetchFailed, username), false
+	}
+}
+
+// parseChannel validates a channel name, @mention or twitch.tv link entered by the user.
+//
+// Parameters:
+//
+//	input - Text entered by the user
+//
+// Returns:
+//
+//	The Twitch login and a *replyError explaining why the input was rejected
+func parseChannel(input string) (string, error) {
+	channel, err := utils.ParseUsername(input)
+	if err == nil {
+		return channel, nil
+	}
+
+	input = strings.TrimSpace(input)
+	switch {
+	case errors.Is(err, utils.ErrUsernameEmpty):
+		return "", &replyError{key: msgUsernameEmpty}
+	case errors.Is(err, utils.ErrUsernameLength):
+		return "", &replyError{key: msgUsernameLength, args: []any{input, utils.MinUsernameLength, utils.MaxUsernameLength}}
+	case errors.Is(err, utils.ErrUsernameChars):
+		return "", &replyError{key: msgUsernameChars, args: []any{input}}
+	case errors.Is(err, utils.ErrNotChannelURL):
+		return "", &replyError{key: msgNotChannelURL, args: []any{input}}
+	default:
+		return "", &replyError{key: msgInvalidChannel, args: []any{input}}
+	}
+}
+
+// replyText returns the localized reply of a *replyError, or the error text for other errors.
+//
+// Parameters:
+//
+//	lang - User's language code
+//	err - Error to describe
+//
+// Returns:
+//
+//	The reply text
+func replyText(lang string, err error) string {
+	var replyErr *replyError
+	if errors.As(err, &replyErr) {
+		return localize(lang, replyErr.key, replyErr.args...)
+	}
+	return err.Error()
+}
@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/export"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/query"
+)
+
+// exportCallbackPrefix is the callback data prefix of export buttons.
+const exportCallbackPrefix = "x:"
+
+// exportButtons maps exported lists to the buttons that fetch them.
+var exportButtons = map[fetcher.Endpoint]string{
+	fetcher.EndpointFollows:  "follows",
+	fetcher.EndpointMods:     "moders",
+	fetcher.EndpointVips:     "vips",
+	fetcher.EndpointFounders: "founders",
+}
+
+// ViewCmdExport creates a view handler for the /export command.
+// It sends a list as a document, e.g. "/export mods xqc xlsx --banned".
+// The format defaults to CSV.
+//
+// Returns:
+//
+//	A ViewFunc that handles the export command interaction
+func (b *Bot) ViewCmdExport() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		args, q, err := query.Parse(update.Message.CommandArguments(), time.Now())
+		if err != nil {
+			return b.sendText(ctx, chatID, replyText(lang, queryReplyError(err)))
+		}
+
+		var (
+			button string
+			format = export.FormatCSV
+			inputs []string
+		)
+		for _, field := range strings.Fields(args) {
+			if list, ok := listAliases[strings.ToLower(field)]; ok && button == "" {
+				button = exportButtons[list]
+				continue
+			}
+			if f, ok := export.ParseFormat(strings.ToLower(field)); ok {
+				format = f
+				continue
+			}
+			inputs = append(inputs, field)
+		}
+		if button == "" || len(inputs) != 1 {
+			return b.sendText(ctx, chatID, localize(lang, msgExportUsage))
+		}
+
+		channel, err := parseChannel(inputs[0])
+		if err != nil {
+			return b.sendText(ctx, chatID, replyText(lang, err))
+		}
+
+		b.exportList(ctx, chatID, lang, channel, button, q, format)
+		return nil
+	}
+}
+
+// exportRow builds the export buttons shown under a list.
+// Buttons whose callback data would exceed Telegram's limit are left out.
+//
+// Parameters:
+//
+//	lang - User's language code
+//	username - Twitch username the list was fetched for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Query the list was rendered with
+//
+// Returns:
+//
+//	The buttons, empty if the list cannot be exported
+func exportRow(lang, username, button string, q query.Query) []tgbotapi.InlineKeyboardButton {
+	if _, ok := listAliases[button]; !ok {
+		return nil
+	}
+
+	var row []tgbotapi.InlineKeyboardButton
+	for _, format := range export.Formats {
+		data := exportCallbackPrefix + string(format) + ":" + listCallbackData(username, button, q)
+		if len(data) > callbackDataLimit {
+			continue
+		}
+		label := localize(lang, msgExportButton, strings.ToUpper(string(format)))
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, data))
+	}
+	return row
+}
+
+// handleExportCallback sends the document requested by an export button.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	callback - Callback query from Telegram
+//	data - Callback data without its prefix
+func (b *Bot) handleExportCallback(ctx context.Context, callback *tgbotapi.CallbackQuery, data string) {
+	name, rest, _ := strings.Cut(data, ":")
+	format, ok := export.ParseFormat(name)
+	if !ok {
+		log.Printf("bot.handleExportCallback: invalid callback data %q", data)
+		return
+	}
+
+	button, username, q := parseListCallback(rest)
+	b.exportList(ctx, callback.Message.Chat.ID, callback.From.LanguageCode, username, button, q, format)
+}
+
+// exportList fetches a list, applies a query and sends the result as a document.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chatID - Telegram chat ID to reply to
+//	lang - User's language code
+//	username - Twitch username to fetch the list for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Query to apply to the list
+//	format - Document format
+func (b *Bot) exportList(ctx context.Context, chatID int64, lang, username, button string, q query.Query, format export.Format) {
+	const op = "bot.exportList"
+
+	rows, err := b.exportRows(ctx, username, button, q)
+	if err != nil {
+		log.Printf("%s: %s %s: %v", op, button, username, err)
+		b.sendFetchError(ctx, chatID, lang, username, button, q, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, rows); err != nil {
+		log.Printf("%s: %v", op, err)
+		return
+	}
+
+	list := listAliases[button]
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  export.Filename(username, list, time.Now(), format),
+		Bytes: buf.Bytes(),
+	})
+	doc.Caption = localize(lang, msgExportCaption, list, username, len(rows))
+
+	if _, err := b.send(ctx, chatID, doc); err != nil {
+		log.Printf("%s: failed to send document: %v", op, err)
+	}
+}
+
+// exportRows fetches a list and converts the entries matching a query into rows.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	username - Twitch username to fetch the list for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Query to apply to the list
+//
+// Returns:
+//
+//	The rows in query order and an error if the list could not be fetched or nothing matches
+func (b *Bot) exportRows(ctx context.Context, username, button string, q query.Query) ([]export.Row, error) {
+	if err := q.Validate(listAliases[button]); err != nil {
+		return nil, queryReplyError(err)
+	}
+
+	var (
+		rows []export.Row
+		err  error
+	)
+	switch button {
+	case "follows":
+		var follows []fetcher.Follow
+		if follows, err = b.fetcher.FetchFollows(ctx, username); err == nil {
+			rows = export.Follows(query.Follows(follows, q))
+		}
+	case "moders":
+		var mods []fetcher.Mod
+		if mods, err = b.fetcher.FetchMods(ctx, username); err == nil {
+			rows = export.Mods(query.Mods(mods, q))
+		}
+	case "vips":
+		var vips []fetcher.Vip
+		if vips, err = b.fetcher.FetchVips(ctx, username); err == nil {
+			rows = export.Vips(query.Vips(vips, q))
+		}
+	case "founders":
+		var founders []fetcher.Founders
+		if founders, err = b.fetcher.FetchFounders(ctx, username); err == nil {
+			rows = export.Founders(query.Founders(founders, q))
+		}
+	default:
+		return nil, fmt.Errorf("unknown button: %s", button)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 && !q.IsZero() {
+		return nil, &replyError{key: msgNoMatches, args: []any{username}}
+	}
+	return rows, nil
+}
@@ -0,0 +1,217 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/query"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+const (
+	flowLookup         = "lookup"  // Pick a list type, then enter a channel
+	flowChanges        = "changes" // Enter a channel, pick a list type and a period
+	flowCallbackPrefix = "fsm:"    // Callback data prefix of flow choice buttons
+	flowStepTimeout    = 5 * time.Minute
+)
+
+// registerFlows declares the bot's built-in dialogs.
+func (b *Bot) registerFlows() {
+	flows := []Flow{
+		{
+			Name:    flowLookup,
+			Start:   "channel",
+			Timeout: flowStepTimeout,
+			Steps: map[string]Step{
+				"channel": {
+					Prompt: msgChannelPrompt,
+					Expect: InputText,
+					Handle: b.handleLookupChannel,
+				},
+			},
+		},
+		{
+			Name:    flowChanges,
+			Start:   "channel",
+			Timeout: flowStepTimeout,
+			Steps: map[string]Step{
+				"channel": {
+					Prompt: msgChannelPrompt,
+					Expect: InputText,
+					Handle: b.handleChangesChannel,
+				},
+				"list": {
+					Prompt: msgChangesListPrompt,
+					Expect: InputCallback,
+					Choices: []Choice{
+						{Label: "follows", Value: "follows"},
+						{Label: "moders", Value: "mods"},
+						{Label: "vips", Value: "vips"},
+						{Label: "founders", Value: "founders"},
+					},
+					Handle: storeAndContinue("list", "period"),
+				},
+				"period": {
+					Prompt: msgChangesPeriodPrompt,
+					Expect: InputCallback | InputText,
+					Choices: []Choice{
+						{Label: "1d", Value: "1d"},
+						{Label: "7d", Value: "7d"},
+						{Label: "30d", Value: "30d"},
+					},
+					Handle: b.handleChangesPeriod,
+				},
+			},
+		},
+	}
+
+	for _, flow := range flows {
+		if err := b.flows.Register(flow); err != nil {
+			panic(fmt.Sprintf("bot.registerFlows: %v", err))
+		}
+	}
+}
+
+// storeAndContinue creates a step handler that saves the input under key and moves to next.
+func storeAndContinue(key, next string) StepHandler {
+	return func(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error) {
+		data[key] = value
+		return Transition{Next: next}, nil
+	}
+}
+
+// handleLookupChannel sends the list chosen on the start keyboard for one or more channels.
+func (b *Bot) handleLookupChannel(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error) {
+	if err := b.lookup(ctx, conv.Key.ChatID, conv.Lang, value, data["list"]); err != nil {
+		return Transition{}, err
+	}
+	return Transition{}, nil
+}
+
+// handleChangesChannel validates the channel whose changes are requested.
+func (b *Bot) handleChangesChannel(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error) {
+	channel, err := parseChannel(value)
+	if err != nil {
+		return Transition{}, err
+	}
+
+	data["channel"] = channel
+	return Transition{Next: "list"}, nil
+}
+
+// handleChangesPeriod validates the period and sends the channel list's changes.
+func (b *Bot) handleChangesPeriod(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error) {
+	if _, err := utils.ParsePeriod(value); err != nil {
+		return Transition{}, &replyError{key: msgInvalidPeriod, args: []any{value}}
+	}
+
+	input := fmt.Sprintf("%s %s %s", data["channel"], data["list"], value)
+	b.respond(ctx, conv.Key.ChatID, conv.Lang, input, "changes", query.Query{})
+	return Transition{}, nil
+}
+
+// startFlow begins a flow and delivers its first prompt.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chat - Update whose message identifies the chat to reply in
+//	conv - Conversation the flow runs in
+//	name - Name of the flow to start
+//	data - Initial flow data, may be nil
+func (b *Bot) startFlow(ctx context.Context, chat tgbotapi.Update, conv Conversation, name string, data map[string]string) {
+	result, err := b.flows.Start(conv, name, data)
+	b.applyFlowResult(ctx, chat, conv, result, err)
+}
+
+// feedFlow passes user input to a running flow.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chat - Update whose message identifies the chat to reply in
+//	conv - Conversation the flow runs in
+//	state - Stored flow state
+//	input - User input
+func (b *Bot) feedFlow(ctx context.Context, chat tgbotapi.Update, conv Conversation, state UserState, input Input) {
+	result, err := b.flows.Feed(ctx, conv, state, input)
+	b.applyFlowResult(ctx, chat, conv, result, err)
+}
+
+// applyFlowResult sends a flow's replies and stores or clears its state.
+// The start keyboard is shown again once a flow finishes.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	chat - Update whose message identifies the chat to reply in
+//	conv - Conversation the flow runs in
+//	result - Flow result to apply
+//	err - Error returned by the flow machine
+func (b *Bot) applyFlowResult(ctx context.Context, chat tgbotapi.Update, conv Conversation, result FlowResult, err error) {
+	const op = "bot.applyFlowResult"
+
+	if err != nil {
+		log.Printf("%s: %v", op, err)
+	}
+
+	for _, reply := range result.Replies {
+		b.sendReply(ctx, conv.Key.ChatID, reply)
+	}
+
+	if result.Done {
+		if err := b.states.Delete(conv.Key); err != nil {
+			log.Printf("%s: failed to delete state: %v", op, err)
+		}
+		b.sendStartKeyboard(ctx, chat)
+		return
+	}
+
+	if err := b.states.Set(conv.Key, result.State); err != nil {
+		log.Printf("%s: failed to save state: %v", op, err)
+	}
+}
+
+// sendReply sends a flow reply, rendering its choices as inline buttons two per row.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	chatID - Telegram chat ID to reply to
+//	reply - Reply to send
+func (b *Bot) sendReply(ctx context.Context, chatID int64, reply Reply) {
+	parts := []string{reply.Text}
+	if reply.HTML {
+		parts = utils.SplitMessage(reply.Text, telegramMessageLimit)
+	}
+
+	for i, part := range parts {
+		msg := tgbotapi.NewMessage(chatID, part)
+		if reply.HTML {
+			msg.ParseMode = tgbotapi.ModeHTML
+			msg.DisableWebPagePreview = true
+		}
+
+		if i == len(parts)-1 && len(reply.Choices) > 0 {
+			var rows [][]tgbotapi.InlineKeyboardButton
+			for j, choice := range reply.Choices {
+				button := tgbotapi.NewInlineKeyboardButtonData(choice.Label, flowCallbackPrefix+choice.Value)
+				if j%2 == 0 {
+					rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
+				} else {
+					rows[len(rows)-1] = append(rows[len(rows)-1], button)
+				}
+			}
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		}
+
+		// Later parts would arrive out of context without the failed one.
+		if _, err := b.send(ctx, chatID, msg); err != nil {
+			log.Printf("bot.sendReply: %v", err)
+			return
+		}
+	}
+}
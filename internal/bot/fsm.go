@@ -0,0 +1,279 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InputKind is a bit set of the input types a flow step accepts.
+type InputKind int
+
+const (
+	InputText     InputKind = 1 << iota // Free-form text message
+	InputCallback                       // Press of one of the step's choice buttons
+	InputNumber                         // Text message containing an integer
+)
+
+// Input is a user's reply to the current flow step.
+type Input struct {
+	Kind  InputKind // InputText for messages or InputCallback for button presses
+	Value string    // Message text or callback value
+}
+
+// Choice is an option offered as an inline button by a step.
+type Choice struct {
+	Label string // Button text
+	Value string // Value passed to the step handler when pressed
+}
+
+// Reply is a message a flow sends to the user.
+type Reply struct {
+	Text    string   // Message text
+	HTML    bool     // Whether Text is HTML formatted
+	Choices []Choice // Inline buttons shown under the message
+}
+
+// Conversation identifies the user a flow is talking to.
+type Conversation struct {
+	Key  StateKey // Chat and user of the conversation
+	Lang string   // User's language code
+}
+
+// Transition is the outcome of handling a step's input.
+type Transition struct {
+	Next    string  // Name of the next step, empty to finish the flow
+	Replies []Reply // Messages sent before the next step's prompt
+}
+
+// StepHandler handles accepted input for a step. It may store values in data,
+// which is carried over to later steps. Returning a *replyError keeps the flow
+// in the current step and shows the error; any other error aborts the flow.
+type StepHandler func(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error)
+
+// Step declares a single state of a flow.
+type Step struct {
+	Prompt  messageKey    // Message asking for the step's input
+	Expect  InputKind     // Accepted input types
+	Choices []Choice      // Buttons offered with the prompt; callback input must match one of them
+	Handle  StepHandler   // Handler for accepted input
+	Timeout time.Duration // Time the user has to answer, zero for the flow default
+}
+
+// Flow declares a multi-step dialog.
+type Flow struct {
+	Name    string          // Unique flow name stored in the conversation state
+	Start   string          // Name of the first step
+	Steps   map[string]Step // Steps by name
+	Timeout time.Duration   // Default time the user has to answer a step, zero for no limit
+}
+
+// FlowResult is the outcome of starting a flow or feeding it input.
+type FlowResult struct {
+	State   UserState // State to store while the flow is running
+	Replies []Reply   // Messages to send to the user
+	Done    bool      // Whether the flow finished and its state should be deleted
+}
+
+// Machine runs declared flows. It does not depend on Telegram, so flows can
+// be driven directly with Input values.
+type Machine struct {
+	flows map[string]Flow
+	now   func() time.Time
+}
+
+// NewMachine creates an empty flow machine.
+//
+// Returns:
+//
+//	A pointer to a new Machine instance
+func NewMachine() *Machine {
+	return &Machine{
+		flows: make(map[string]Flow),
+		now:   time.Now,
+	}
+}
+
+// Register adds a flow to the machine.
+//
+// Parameters:
+//
+//	flow - Flow declaration
+//
+// Returns:
+//
+//	An error if the flow is invalid or its name is already registered
+func (m *Machine) Register(flow Flow) error {
+	if _, ok := m.flows[flow.Name]; ok {
+		return fmt.Errorf("flow %q already registered", flow.Name)
+	}
+
+	if _, ok := flow.Steps[flow.Start]; !ok {
+		return fmt.Errorf("flow %q: unknown start step %q", flow.Name, flow.Start)
+	}
+
+	for name, step := range flow.Steps {
+		if step.Handle == nil || step.Expect == 0 {
+			return fmt.Errorf("flow %q: step %q needs a handler and an input kind", flow.Name, name)
+		}
+	}
+
+	m.flows[flow.Name] = flow
+	return nil
+}
+
+// Start begins a flow and returns the prompt of its first step.
+//
+// Parameters:
+//
+//	conv - Conversation the flow runs in
+//	name - Name of the flow to start
+//	data - Initial flow data, may be nil
+//
+// Returns:
+//
+//	The flow result and an error if the flow is unknown
+func (m *Machine) Start(conv Conversation, name string, data map[string]string) (FlowResult, error) {
+	flow, ok := m.flows[name]
+	if !ok {
+		return FlowResult{Done: true}, fmt.Errorf("unknown flow: %s", name)
+	}
+
+	if data == nil {
+		data = make(map[string]string)
+	}
+
+	state := UserState{Flow: name, Step: flow.Start, Data: data}
+	return m.enter(conv, flow, state, nil), nil
+}
+
+// Feed passes user input to the current step of a running flow.
+//
+// Parameters:
+//
+//	ctx - Context for the step handler
+//	conv - Conversation the flow runs in
+//	state - Stored state of the flow
+//	input - User input
+//
+// Returns:
+//
+//	The flow result and an error if the flow failed
+func (m *Machine) Feed(ctx context.Context, conv Conversation, state UserState, input Input) (FlowResult, error) {
+	flow, ok := m.flows[state.Flow]
+	if !ok {
+		return FlowResult{Done: true}, fmt.Errorf("unknown flow: %s", state.Flow)
+	}
+
+	step, ok := flow.Steps[state.Step]
+	if !ok {
+		return FlowResult{Done: true}, fmt.Errorf("flow %q: unknown step %q", flow.Name, state.Step)
+	}
+
+	if !state.ExpiresAt.IsZero() && m.now().After(state.ExpiresAt) {
+		return FlowResult{Done: true, Replies: []Reply{{Text: localize(conv.Lang, msgFlowExpired)}}}, nil
+	}
+
+	value, problem := accept(step, input)
+	if problem != "" {
+		return m.stay(conv, step, state, localize(conv.Lang, problem)), nil
+	}
+
+	if state.Data == nil {
+		state.Data = make(map[string]string)
+	}
+
+	transition, err := step.Handle(ctx, conv, state.Data, value)
+	if err != nil {
+		var replyErr *replyError
+		if errors.As(err, &replyErr) {
+			return m.stay(conv, step, state, localize(conv.Lang, replyErr.key, replyErr.args...)), nil
+		}
+		return FlowResult{Done: true}, err
+	}
+
+	if transition.Next == "" {
+		return FlowResult{Done: true, Replies: transition.Replies}, nil
+	}
+
+	if _, ok := flow.Steps[transition.Next]; !ok {
+		return FlowResult{Done: true}, fmt.Errorf("flow %q: unknown step %q", flow.Name, transition.Next)
+	}
+
+	state.Step = transition.Next
+	return m.enter(conv, flow, state, transition.Replies), nil
+}
+
+// enter moves the flow into its current step, prompting the user and setting the step deadline.
+func (m *Machine) enter(conv Conversation, flow Flow, state UserState, replies []Reply) FlowResult {
+	step := flow.Steps[state.Step]
+
+	timeout := step.Timeout
+	if timeout == 0 {
+		timeout = flow.Timeout
+	}
+
+	state.ExpiresAt = time.Time{}
+	if timeout > 0 {
+		state.ExpiresAt = m.now().Add(timeout)
+	}
+
+	replies = append(replies, Reply{Text: localize(conv.Lang, step.Prompt), Choices: step.Choices})
+	return FlowResult{State: state, Replies: replies}
+}
+
+// stay keeps the flow in its current step and repeats the prompt after a problem message.
+func (m *Machine) stay(conv Conversation, step Step, state UserState, problem string) FlowResult {
+	return FlowResult{
+		State: state,
+		Replies: []Reply{
+			{Text: problem},
+			{Text: localize(conv.Lang, step.Prompt), Choices: step.Choices},
+		},
+	}
+}
+
+// accept validates input against a step's expectations.
+//
+// Parameters:
+//
+//	step - Current step
+//	input - User input
+//
+// Returns:
+//
+//	The accepted value, or a message key describing why the input was rejected
+func accept(step Step, input Input) (string, messageKey) {
+	value := strings.TrimSpace(input.Value)
+
+	switch {
+	case input.Kind == InputCallback && step.Expect&InputCallback != 0:
+		for _, choice := range step.Choices {
+			if choice.Value == value {
+				return value, ""
+			}
+		}
+		return "", msgFlowChooseOption
+
+	case input.Kind == InputText && step.Expect&InputText != 0:
+		if value == "" {
+			return "", msgFlowEmptyInput
+		}
+		return value, ""
+
+	case input.Kind == InputText && step.Expect&InputNumber != 0:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", msgFlowExpectNumber
+		}
+		return value, ""
+
+	case step.Expect&InputCallback != 0:
+		return "", msgFlowChooseOption
+
+	default:
+		return "", msgFlowExpectText
+	}
+}
@@ -0,0 +1,258 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testFlow declares a three-step flow: a channel name, a list choice and a
+// number of days. Typing "cancel" at any text step finishes the flow early.
+func testFlow() Flow {
+	return Flow{
+		Name:    "test",
+		Start:   "channel",
+		Timeout: time.Minute,
+		Steps: map[string]Step{
+			"channel": {
+				Prompt: msgChannelPrompt,
+				Expect: InputText,
+				Handle: func(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error) {
+					switch value {
+					case "cancel":
+						return Transition{Replies: []Reply{{Text: "cancelled"}}}, nil
+					case "invalid":
+						return Transition{}, &replyError{key: msgUsernameEmpty}
+					case "broken":
+						return Transition{}, errors.New("upstream failure")
+					}
+					data["channel"] = value
+					return Transition{Next: "list"}, nil
+				},
+			},
+			"list": {
+				Prompt: msgChangesListPrompt,
+				Expect: InputCallback,
+				Choices: []Choice{
+					{Label: "mods", Value: "mods"},
+					{Label: "vips", Value: "vips"},
+				},
+				Handle: storeAndContinue("list", "days"),
+			},
+			"days": {
+				Prompt:  msgChangesPeriodPrompt,
+				Expect:  InputNumber,
+				Timeout: 10 * time.Second,
+				Handle: func(ctx context.Context, conv Conversation, data map[string]string, value string) (Transition, error) {
+					data["days"] = value
+					return Transition{Replies: []Reply{{Text: "done"}}}, nil
+				},
+			},
+		},
+	}
+}
+
+// newTestMachine creates a machine with testFlow registered and a clock the test controls.
+func newTestMachine(t *testing.T, now *time.Time) *Machine {
+	t.Helper()
+
+	m := NewMachine()
+	m.now = func() time.Time { return *now }
+	if err := m.Register(testFlow()); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return m
+}
+
+func TestMachineRegister(t *testing.T) {
+	handle := storeAndContinue("key", "")
+
+	tests := []struct {
+		name string
+		flow Flow
+	}{
+		{"duplicate name", testFlow()},
+		{"unknown start step", Flow{Name: "a", Start: "missing", Steps: map[string]Step{"s": {Expect: InputText, Handle: handle}}}},
+		{"step without handler", Flow{Name: "b", Start: "s", Steps: map[string]Step{"s": {Expect: InputText}}}},
+		{"step without input kind", Flow{Name: "c", Start: "s", Steps: map[string]Step{"s": {Handle: handle}}}},
+	}
+
+	now := time.Now()
+	m := newTestMachine(t, &now)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.Register(tt.flow); err == nil {
+				t.Error("Register succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestMachineFeed(t *testing.T) {
+	conv := Conversation{Key: StateKey{ChatID: 1, UserID: 2}, Lang: "en"}
+	text := func(v string) Input { return Input{Kind: InputText, Value: v} }
+	press := func(v string) Input { return Input{Kind: InputCallback, Value: v} }
+
+	type feed struct {
+		after time.Duration // Time passed before the input
+		input Input
+	}
+
+	tests := []struct {
+		name      string
+		feeds     []feed
+		wantStep  string            // Step after the last input, empty if the flow is done
+		wantData  map[string]string // Flow data after the last input, nil to skip
+		wantReply string            // First reply to the last input, empty to skip
+		wantErr   bool
+	}{
+		{
+			name:     "transitions through every step",
+			feeds:    []feed{{0, text(" xqc ")}, {0, press("vips")}, {0, text("7")}},
+			wantData: map[string]string{"channel": "xqc", "list": "vips", "days": "7"},
+		},
+		{
+			name:     "moves to the next step",
+			feeds:    []feed{{0, text("xqc")}},
+			wantStep: "list",
+			wantData: map[string]string{"channel": "xqc"},
+		},
+		{
+			name:      "handler finishes the flow early on cancel",
+			feeds:     []feed{{0, text("cancel")}},
+			wantReply: "cancelled",
+		},
+		{
+			name:      "empty text keeps the step",
+			feeds:     []feed{{0, text("   ")}},
+			wantStep:  "channel",
+			wantReply: localize("en", msgFlowEmptyInput),
+		},
+		{
+			name:      "button press where text is expected",
+			feeds:     []feed{{0, press("mods")}},
+			wantStep:  "channel",
+			wantReply: localize("en", msgFlowExpectText),
+		},
+		{
+			name:      "text where a button is expected",
+			feeds:     []feed{{0, text("xqc")}, {0, text("mods")}},
+			wantStep:  "list",
+			wantReply: localize("en", msgFlowChooseOption),
+		},
+		{
+			name:      "press of a button not offered",
+			feeds:     []feed{{0, text("xqc")}, {0, press("founders")}},
+			wantStep:  "list",
+			wantReply: localize("en", msgFlowChooseOption),
+		},
+		{
+			name:      "text that is not a number",
+			feeds:     []feed{{0, text("xqc")}, {0, press("mods")}, {0, text("seven")}},
+			wantStep:  "days",
+			wantReply: localize("en", msgFlowExpectNumber),
+		},
+		{
+			name:      "reply error from the handler keeps the step",
+			feeds:     []feed{{0, text("invalid")}},
+			wantStep:  "channel",
+			wantReply: localize("en", msgUsernameEmpty),
+		},
+		{
+			name:    "other handler errors abort the flow",
+			feeds:   []feed{{0, text("broken")}},
+			wantErr: true,
+		},
+		{
+			name:     "answer within the flow timeout",
+			feeds:    []feed{{59 * time.Second, text("xqc")}},
+			wantStep: "list",
+		},
+		{
+			name:      "answer after the flow timeout",
+			feeds:     []feed{{61 * time.Second, text("xqc")}},
+			wantReply: localize("en", msgFlowExpired),
+		},
+		{
+			name:      "step timeout overrides the flow timeout",
+			feeds:     []feed{{0, text("xqc")}, {0, press("mods")}, {11 * time.Second, text("7")}},
+			wantReply: localize("en", msgFlowExpired),
+		},
+		{
+			name:     "each step restarts the deadline",
+			feeds:    []feed{{50 * time.Second, text("xqc")}, {50 * time.Second, press("mods")}},
+			wantStep: "days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			m := newTestMachine(t, &now)
+
+			result, err := m.Start(conv, "test", nil)
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			if got := result.State.ExpiresAt; !got.Equal(now.Add(time.Minute)) {
+				t.Fatalf("Start: deadline %v, want %v", got, now.Add(time.Minute))
+			}
+			data := result.State.Data // Shared by every step of the flow
+
+			for i, f := range tt.feeds {
+				if result.Done {
+					t.Fatalf("flow finished before input %d", i)
+				}
+				now = now.Add(f.after)
+				result, err = m.Feed(context.Background(), conv, result.State, f.input)
+				if i < len(tt.feeds)-1 && err != nil {
+					t.Fatalf("Feed %d: %v", i, err)
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Feed: error %v, want error %t", err, tt.wantErr)
+			}
+
+			if tt.wantStep == "" {
+				if !result.Done {
+					t.Errorf("flow in step %q, want it done", result.State.Step)
+				}
+			} else if result.Done || result.State.Step != tt.wantStep {
+				t.Errorf("flow in step %q (done %t), want %q", result.State.Step, result.Done, tt.wantStep)
+			}
+
+			for k, want := range tt.wantData {
+				if got := data[k]; got != want {
+					t.Errorf("data[%q] = %q, want %q", k, got, want)
+				}
+			}
+
+			if tt.wantReply != "" {
+				if len(result.Replies) == 0 {
+					t.Fatalf("no replies, want %q", tt.wantReply)
+				}
+				if got := result.Replies[0].Text; got != tt.wantReply {
+					t.Errorf("reply %q, want %q", got, tt.wantReply)
+				}
+			}
+		})
+	}
+}
+
+func TestMachineUnknownFlow(t *testing.T) {
+	now := time.Now()
+	m := newTestMachine(t, &now)
+	conv := Conversation{Lang: "en"}
+
+	if result, err := m.Start(conv, "missing", nil); err == nil || !result.Done {
+		t.Errorf("Start of an unknown flow: done %t, error %v", result.Done, err)
+	}
+
+	state := UserState{Flow: "test", Step: "missing"}
+	if result, err := m.Feed(context.Background(), conv, state, Input{Kind: InputText, Value: "x"}); err == nil || !result.Done {
+		t.Errorf("Feed to an unknown step: done %t, error %v", result.Done, err)
+	}
+}
@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// limiterSweepInterval is how often idle buckets and stale cooldowns are dropped.
+const limiterSweepInterval = 10 * time.Minute
+
+// RateLimits configures how many requests users and chats may send.
+// A zero burst disables the corresponding bucket.
+type RateLimits struct {
+	UserBurst    int           // Requests a user may send at once
+	UserInterval time.Duration // Time for a user to regain one request
+	ChatBurst    int           // Requests a chat may send at once
+	ChatInterval time.Duration // Time for a chat to regain one request
+	DailyQuota   int           // Requests per user per UTC day, zero for unlimited
+}
+
+// limitVerdict is the outcome of a rate limit check.
+type limitVerdict struct {
+	allowed bool
+	quota   bool          // Whether the daily quota was exceeded rather than a bucket
+	wait    time.Duration // Time until a request is allowed again
+	notify  bool          // Whether the user should be told, once per cooldown
+}
+
+// tokenBucket is a token bucket refilled continuously.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// refill adds the tokens earned since the last update, up to burst.
+func (t *tokenBucket) refill(now time.Time, burst int, interval time.Duration) {
+	if interval > 0 {
+		t.tokens += float64(now.Sub(t.updated)) / float64(interval)
+	}
+	t.tokens = min(t.tokens, float64(burst))
+	t.updated = now
+}
+
+// wait returns the time until the bucket holds one token.
+func (t *tokenBucket) wait(interval time.Duration) time.Duration {
+	if t.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - t.tokens) * float64(interval))
+}
+
+// limiter enforces per-user and per-chat token buckets and a per-user daily quota.
+type limiter struct {
+	limits RateLimits
+
+	mu        sync.Mutex
+	users     map[int64]*tokenBucket
+	chats     map[int64]*tokenBucket
+	day       time.Time           // UTC day the quota counts belong to
+	used      map[int64]int       // Requests per user on day
+	cooldowns map[int64]time.Time // Time until which a user, or chat if unknown, was told to wait
+	lastSweep time.Time
+}
+
+// newLimiter creates a limiter.
+//
+// Parameters:
+//
+//	limits - Rate limits to enforce
+//
+// Returns:
+//
+//	A pointer to a new limiter instance
+func newLimiter(limits RateLimits) *limiter {
+	return &limiter{
+		limits:    limits,
+		users:     make(map[int64]*tokenBucket),
+		chats:     make(map[int64]*tokenBucket),
+		used:      make(map[int64]int),
+		cooldowns: make(map[int64]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow checks a request against the limits and consumes a token from the
+// user's and the chat's bucket and a unit of the user's quota if it is allowed.
+// Nothing is consumed for a rejected request.
+//
+// Parameters:
+//
+//	userID - Telegram user ID, zero if the sender is unknown
+//	chatID - Telegram chat ID
+//	now - Current time
+//
+// Returns:
+//
+//	The verdict
+func (l *limiter) allow(userID, chatID int64, now time.Time) limitVerdict {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	if day := now.UTC().Truncate(24 * time.Hour); !day.Equal(l.day) {
+		l.day = day
+		clear(l.used)
+	}
+
+	if userID != 0 && l.limits.DailyQuota > 0 && l.used[userID] >= l.limits.DailyQuota {
+		return l.reject(userID, chatID, limitVerdict{quota: true, wait: l.day.Add(24 * time.Hour).Sub(now)}, now)
+	}
+
+	user := l.bucket(l.users, userID, l.limits.UserBurst, l.limits.UserInterval, now)
+	chat := l.bucket(l.chats, chatID, l.limits.ChatBurst, l.limits.ChatInterval, now)
+
+	var wait time.Duration
+	if user != nil {
+		wait = user.wait(l.limits.UserInterval)
+	}
+	if chat != nil {
+		wait = max(wait, chat.wait(l.limits.ChatInterval))
+	}
+	if wait > 0 {
+		return l.reject(userID, chatID, limitVerdict{wait: wait}, now)
+	}
+
+	if user != nil {
+		user.tokens--
+	}
+	if chat != nil {
+		chat.tokens--
+	}
+	if userID != 0 {
+		l.used[userID]++
+	}
+
+	return limitVerdict{allowed: true}
+}
+
+// reject completes the verdict of a rejected request, asking for a notification
+// only for the first rejection of a cooldown. The caller must hold l.mu.
+func (l *limiter) reject(userID, chatID int64, verdict limitVerdict, now time.Time) limitVerdict {
+	id := userID
+	if id == 0 {
+		id = chatID
+	}
+
+	if until, ok := l.cooldowns[id]; !ok || !now.Before(until) {
+		l.cooldowns[id] = now.Add(verdict.wait)
+		verdict.notify = true
+	}
+
+	return verdict
+}
+
+// bucket returns the refilled bucket of a user or chat, creating a full one if needed.
+// It returns nil if the bucket is disabled or the ID is unknown.
+func (l *limiter) bucket(buckets map[int64]*tokenBucket, id int64, burst int, interval time.Duration, now time.Time) *tokenBucket {
+	if burst <= 0 || id == 0 {
+		return nil
+	}
+
+	b, ok := buckets[id]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), updated: now}
+		buckets[id] = b
+	}
+	b.refill(now, burst, interval)
+	return b
+}
+
+// sweep drops full buckets and expired cooldowns, at most once per limiterSweepInterval.
+// The caller must hold l.mu.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	sweepBuckets(l.users, l.limits.UserBurst, l.limits.UserInterval, now)
+	sweepBuckets(l.chats, l.limits.ChatBurst, l.limits.ChatInterval, now)
+
+	for id, until := range l.cooldowns {
+		if !now.Before(until) {
+			delete(l.cooldowns, id)
+		}
+	}
+}
+
+// sweepBuckets drops buckets that refilled completely, as they equal a new bucket.
+func sweepBuckets(buckets map[int64]*tokenBucket, burst int, interval time.Duration, now time.Time) {
+	for id, b := range buckets {
+		if b.refill(now, burst, interval); b.tokens >= float64(burst) {
+			delete(buckets, id)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type request struct {
+		after  time.Duration // Time passed before the request
+		userID int64
+		chatID int64
+		want   limitVerdict
+	}
+
+	allowed := limitVerdict{allowed: true}
+
+	tests := []struct {
+		name     string
+		limits   RateLimits
+		requests []request
+	}{
+		{
+			name:   "burst then wait for one token",
+			limits: RateLimits{UserBurst: 2, UserInterval: 10 * time.Second},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{0, 1, 100, allowed},
+				{0, 1, 100, limitVerdict{wait: 10 * time.Second, notify: true}},
+				{4 * time.Second, 1, 100, limitVerdict{wait: 6 * time.Second}},
+				{6 * time.Second, 1, 100, allowed},
+				{0, 1, 100, limitVerdict{wait: 10 * time.Second, notify: true}},
+			},
+		},
+		{
+			name:   "refill stops at the burst",
+			limits: RateLimits{UserBurst: 2, UserInterval: time.Second},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{time.Hour, 1, 100, allowed},
+				{0, 1, 100, allowed},
+				{0, 1, 100, limitVerdict{wait: time.Second, notify: true}},
+			},
+		},
+		{
+			name:   "users are limited separately",
+			limits: RateLimits{UserBurst: 1, UserInterval: time.Minute},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{0, 2, 100, allowed},
+				{0, 1, 100, limitVerdict{wait: time.Minute, notify: true}},
+			},
+		},
+		{
+			name:   "chat limit applies to every user in the chat",
+			limits: RateLimits{UserBurst: 5, UserInterval: time.Second, ChatBurst: 2, ChatInterval: time.Minute},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{0, 2, 100, allowed},
+				{0, 3, 100, limitVerdict{wait: time.Minute, notify: true}},
+				{0, 3, 200, allowed},
+			},
+		},
+		{
+			name:   "rejected requests consume nothing",
+			limits: RateLimits{UserBurst: 1, UserInterval: time.Minute, ChatBurst: 1, ChatInterval: 10 * time.Second},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{0, 2, 100, limitVerdict{wait: 10 * time.Second, notify: true}},
+				// The rejection above must not have used user 2's token.
+				{10 * time.Second, 2, 100, allowed},
+			},
+		},
+		{
+			name:   "daily quota resets at midnight UTC",
+			limits: RateLimits{DailyQuota: 2},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{0, 1, 100, allowed},
+				{0, 1, 100, limitVerdict{quota: true, wait: 12 * time.Hour, notify: true}},
+				{11 * time.Hour, 1, 100, limitVerdict{quota: true, wait: time.Hour}},
+				{time.Hour, 1, 100, allowed},
+			},
+		},
+		{
+			name:   "unknown senders only count against the chat",
+			limits: RateLimits{UserBurst: 1, UserInterval: time.Minute, ChatBurst: 2, ChatInterval: time.Minute, DailyQuota: 1},
+			requests: []request{
+				{0, 0, 100, allowed},
+				{0, 0, 100, allowed},
+				{0, 0, 100, limitVerdict{wait: time.Minute, notify: true}},
+			},
+		},
+		{
+			name:   "no limits",
+			limits: RateLimits{},
+			requests: []request{
+				{0, 1, 100, allowed},
+				{0, 1, 100, allowed},
+				{0, 1, 100, allowed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(tt.limits)
+			now := start
+
+			for i, r := range tt.requests {
+				now = now.Add(r.after)
+				if got := l.allow(r.userID, r.chatID, now); got != r.want {
+					t.Errorf("request %d: verdict %+v, want %+v", i, got, r.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(RateLimits{UserBurst: 1, UserInterval: time.Minute})
+
+	l.allow(1, 100, now)
+	l.allow(1, 100, now)
+	if len(l.users) != 1 || len(l.cooldowns) != 1 {
+		t.Fatalf("%d buckets and %d cooldowns, want 1 each", len(l.users), len(l.cooldowns))
+	}
+
+	// After the sweep interval the bucket is full again and the cooldown over.
+	l.allow(2, 100, now.Add(limiterSweepInterval+time.Second))
+	if _, ok := l.users[1]; ok {
+		t.Error("refilled bucket was kept")
+	}
+	if _, ok := l.cooldowns[1]; ok {
+		t.Error("expired cooldown was kept")
+	}
+}
+
+func TestFormatWait(t *testing.T) {
+	tests := []struct {
+		wait time.Duration
+		want string
+	}{
+		{100 * time.Millisecond, "1s"},
+		{7 * time.Second, "7s"},
+		{12 * time.Minute, "12m"},
+		{5*time.Hour + 32*time.Minute + 10*time.Second, "5h32m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatWait(tt.wait); got != tt.want {
+			t.Errorf("formatWait(%s) = %q, want %q", tt.wait, got, tt.want)
+		}
+	}
+}
+
+func TestCommandsRateLimited(t *testing.T) {
+	b := &Bot{
+		states:  NewMemoryStateStore(time.Minute),
+		access:  NewMemoryAccessStore(),
+		admins:  map[int64]bool{9: true},
+		limiter: newLimiter(RateLimits{UserBurst: 1, UserInterval: time.Hour}),
+		sender:  newSender(nil, defaultSendLimits),
+	}
+
+	calls := make(map[string]int)
+	view := func(name string) ViewFunc {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			calls[name]++
+			return nil
+		}
+	}
+	b.RegisterCommand("start", view("start"), WithoutRateLimit())
+	b.RegisterCommand("mods", view("mods"), WithAliases("m"))
+
+	command := func(userID int64, text string) tgbotapi.Update {
+		return tgbotapi.Update{Message: &tgbotapi.Message{
+			Text:     text,
+			Chat:     &tgbotapi.Chat{ID: 100},
+			From:     &tgbotapi.User{ID: userID},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Length: len(text)}},
+		}}
+	}
+
+	// The cooldown notice cannot be delivered without an API, so it gives up quickly.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	for _, update := range []tgbotapi.Update{
+		command(1, "/mods"), command(1, "/m"), command(1, "/start"), command(1, "/start"),
+		command(9, "/mods"), command(9, "/mods"),
+	} {
+		b.handleUpdate(ctx, update)
+	}
+
+	if calls["mods"] != 3 {
+		t.Errorf("/mods handled %d times, want once for the user and twice for the admin", calls["mods"])
+	}
+	if calls["start"] != 2 {
+		t.Errorf("/start handled %d times, want 2", calls["start"])
+	}
+}
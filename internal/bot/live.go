@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/formatter"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
+)
+
+// LiveStore defines an interface for persisting go-live registrations.
+type LiveStore interface {
+	SaveLiveSubscription(sub storage.LiveSubscription) error
+	DeleteLiveSubscription(chatID int64) (bool, error)
+	LiveSubscription(chatID int64) (storage.LiveSubscription, bool, error)
+	AllLiveSubscriptions() ([]storage.LiveSubscription, error)
+}
+
+// livePollTimeout bounds a single follow list poll.
+const livePollTimeout = 30 * time.Second
+
+// liveNotifier polls the follow lists of registered users and remembers which channels were live.
+type liveNotifier struct {
+	subs       LiveStore                  // Persisted registrations
+	interval   time.Duration              // Time between polls
+	maxPerHour int                        // Maximum notifications per chat per hour, zero for unlimited
+	wasLive    map[string]map[string]bool // Live channels per polled login at the previous poll
+	sent       map[int64][]time.Time      // Notification times per chat within the last hour
+}
+
+// runLiveNotifier polls the follow lists of all registered users until the context is done.
+//
+// Parameters:
+//
+//	ctx - Context controlling the notifier's lifecycle
+func (b *Bot) runLiveNotifier(ctx context.Context) {
+	ticker := time.NewTicker(b.live.interval)
+	defer ticker.Stop()
+
+	for {
+		// The next poll is attempted even if this one panicked.
+		_ = b.guard(ctx, "go-live poll", func() error {
+			b.pollLive(ctx)
+			return nil
+		})
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollLive fetches every registered login's follows once, regardless of how
+// many chats registered it, and notifies chats about channels that went live.
+// The first poll of a login only records the current state.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+func (b *Bot) pollLive(ctx context.Context) {
+	const op = "bot.pollLive"
+
+	subs, err := b.live.subs.AllLiveSubscriptions()
+	if err != nil {
+		log.Printf("%s: %v", op, err)
+		return
+	}
+
+	registrations := make(map[string][]storage.LiveSubscription)
+	for _, sub := range subs {
+		registrations[sub.Login] = append(registrations[sub.Login], sub)
+	}
+
+	now := time.Now()
+
+	for login, chats := range registrations {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A login that makes the poll panic must not keep the others from being notified.
+		err := b.guard(ctx, "go-live poll of "+login, func() error {
+			return b.pollLiveLogin(ctx, login, chats, now)
+		})
+		if err != nil {
+			log.Printf("%s: %s: %v", op, login, err)
+		}
+	}
+
+	for login := range b.live.wasLive {
+		if _, ok := registrations[login]; !ok {
+			delete(b.live.wasLive, login)
+		}
+	}
+}
+
+// pollLiveLogin fetches a login's follows and notifies the registered chats
+// about channels that went live since the previous poll.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	login - Registered Twitch login
+//	chats - Registrations of the login
+//	now - Time of the poll
+//
+// Returns:
+//
+//	An error if the follows could not be fetched
+func (b *Bot) pollLiveLogin(ctx context.Context, login string, chats []storage.LiveSubscription, now time.Time) error {
+	const op = "bot.pollLiveLogin"
+
+	pollCtx, cancel := context.WithTimeout(ctx, livePollTimeout)
+	follows, err := b.liveFetch.FetchFollows(pollCtx, login)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	previous, seen := b.live.wasLive[login]
+	current := make(map[string]bool)
+
+	for _, follow := range follows {
+		if !follow.IsLive {
+			continue
+		}
+
+		channel := strings.ToLower(follow.Login)
+		current[channel] = true
+		if !seen || previous[channel] {
+			continue
+		}
+
+		text := formatter.FormatLiveNotification(follow)
+		for _, sub := range chats {
+			if slices.Contains(sub.Muted, channel) || inQuietHours(sub, now) || !b.live.allow(sub.ChatID, now) {
+				continue
+			}
+
+			msg := tgbotapi.NewMessage(sub.ChatID, text)
+			msg.ParseMode = tgbotapi.ModeHTML
+			msg.DisableWebPagePreview = true
+			if _, err := b.send(ctx, sub.ChatID, msg); err != nil {
+				log.Printf("%s: failed to notify chat %d: %v", op, sub.ChatID, err)
+			}
+		}
+	}
+
+	b.live.wasLive[login] = current
+	return nil
+}
+
+// allow records a notification for a chat if it is still within the hourly cap.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//	now - Current time
+//
+// Returns:
+//
+//	True if the notification may be sent
+func (n *liveNotifier) allow(chatID int64, now time.Time) bool {
+	recent := slices.DeleteFunc(n.sent[chatID], func(t time.Time) bool {
+		return now.Sub(t) >= time.Hour
+	})
+
+	if n.maxPerHour > 0 && len(recent) >= n.maxPerHour {
+		n.sent[chatID] = recent
+		return false
+	}
+
+	n.sent[chatID] = append(recent, now)
+	return true
+}
+
+// inQuietHours reports whether now falls into the registration's quiet hours.
+//
+// Parameters:
+//
+//	sub - Go-live registration
+//	now - Current time
+//
+// Returns:
+//
+//	True if notifications should be suppressed
+func inQuietHours(sub storage.LiveSubscription, now time.Time) bool {
+	if sub.QuietFrom == sub.QuietTo {
+		return false
+	}
+
+	hour := now.UTC().Add(time.Duration(sub.UTCOffset) * time.Hour).Hour()
+	if sub.QuietFrom < sub.QuietTo {
+		return hour >= sub.QuietFrom && hour < sub.QuietTo
+	}
+	return hour >= sub.QuietFrom || hour < sub.QuietTo
+}
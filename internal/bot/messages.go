@@ -0,0 +1,389 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// messageKey identifies a user-facing message in the localization catalog.
+type messageKey string
+
+const (
+	msgUserNotFound        messageKey = "user_not_found"
+	msgEmptyFollows        messageKey = "empty_follows"
+	msgEmptyMods           messageKey = "empty_mods"
+	msgEmptyVips           messageKey = "empty_vips"
+	msgEmptyFounders       messageKey = "empty_founders"
+	msgEmptyList           messageKey = "empty_list"
+	msgRateLimited         messageKey = "rate_limited"
+	msgUnavailable         messageKey = "unavailable"
+	msgTimeout             messageKey = "timeout"
+	msgFetchFailed         messageKey = "fetch_failed"
+	msgRetryButton         messageKey = "retry_button"
+	msgChannelPrompt       messageKey = "channel_prompt"
+	msgHistoryDisabled     messageKey = "history_disabled"
+	msgNoHistory           messageKey = "no_history"
+	msgInvalidChanges      messageKey = "invalid_changes"
+	msgWatchUsage          messageKey = "watch_usage"
+	msgWatchLimit          messageKey = "watch_limit"
+	msgWatchAdded          messageKey = "watch_added"
+	msgUnwatchUsage        messageKey = "unwatch_usage"
+	msgNotWatching         messageKey = "not_watching"
+	msgUnwatched           messageKey = "unwatched"
+	msgWatchlistEmpty      messageKey = "watchlist_empty"
+	msgWatchlistHeader     messageKey = "watchlist_header"
+	msgGoLiveUsage         messageKey = "golive_usage"
+	msgGoLiveNotRegistered messageKey = "golive_not_registered"
+	msgGoLiveDisabled      messageKey = "golive_disabled"
+	msgGoLiveEnabled       messageKey = "golive_enabled"
+	msgGoLiveStatus        messageKey = "golive_status"
+	msgMuteUsage           messageKey = "mute_usage"
+	msgMuted               messageKey = "muted"
+	msgUnmuteUsage         messageKey = "unmute_usage"
+	msgUnmuted             messageKey = "unmuted"
+	msgMutedList           messageKey = "muted_list"
+	msgQuietUsage          messageKey = "quiet_usage"
+	msgQuietEnabled        messageKey = "quiet_enabled"
+	msgQuietDisabled       messageKey = "quiet_disabled"
+	msgFlowExpired         messageKey = "flow_expired"
+	msgFlowChooseOption    messageKey = "flow_choose_option"
+	msgFlowEmptyInput      messageKey = "flow_empty_input"
+	msgFlowExpectNumber    messageKey = "flow_expect_number"
+	msgFlowExpectText      messageKey = "flow_expect_text"
+	msgChangesListPrompt   messageKey = "changes_list_prompt"
+	msgChangesPeriodPrompt messageKey = "changes_period_prompt"
+	msgInvalidPeriod       messageKey = "invalid_period"
+	msgInvalidChannel      messageKey = "invalid_channel"
+	msgUsernameEmpty       messageKey = "username_empty"
+	msgUsernameLength      messageKey = "username_length"
+	msgUsernameChars       messageKey = "username_chars"
+	msgNotChannelURL       messageKey = "not_channel_url"
+	msgBatchTooLarge       messageKey = "batch_too_large"
+	msgBatchSummary        messageKey = "batch_summary"
+	msgOverlapUsage        messageKey = "overlap_usage"
+	msgOverlapTooFew       messageKey = "overlap_too_few"
+	msgMutualUsage         messageKey = "mutual_usage"
+	msgNoMatches           messageKey = "no_matches"
+	msgInvalidFlag         messageKey = "invalid_flag"
+	msgUnsupportedFlag     messageKey = "unsupported_flag"
+	msgSortOldest          messageKey = "sort_oldest"
+	msgSortNewest          messageKey = "sort_newest"
+	msgSortName            messageKey = "sort_name"
+	msgSortLive            messageKey = "sort_live"
+	msgFilterLive          messageKey = "filter_live"
+	msgFilterBanned        messageKey = "filter_banned"
+	msgFilterSubscribed    messageKey = "filter_subscribed"
+	msgQueryReset          messageKey = "query_reset"
+	msgPageExpired         messageKey = "page_expired"
+	msgExportUsage         messageKey = "export_usage"
+	msgExportButton        messageKey = "export_button"
+	msgExportCaption       messageKey = "export_caption"
+	msgInternalError       messageKey = "internal_error"
+	msgCooldown            messageKey = "cooldown"
+	msgQuotaExceeded       messageKey = "quota_exceeded"
+	msgAdminOnly           messageKey = "admin_only"
+	msgAccessUsage         messageKey = "access_usage"
+	msgAccessAllowed       messageKey = "access_allowed"
+	msgAccessDenied        messageKey = "access_denied"
+	msgAccessRemoved       messageKey = "access_removed"
+	msgAccessNotListed     messageKey = "access_not_listed"
+	msgAccessList          messageKey = "access_list"
+)
+
+// defaultLanguage is used when the user's language has no catalog entry.
+const defaultLanguage = "en"
+
+// catalog holds the user-facing messages per language code.
+var catalog = map[string]map[messageKey]string{
+	"en": {
+		msgUserNotFound:        "Channel %s was not found on Twitch.",
+		msgEmptyFollows:        "%s does not follow any channel.",
+		msgEmptyMods:           "%s does not have any moderators.",
+		msgEmptyVips:           "%s does not have any VIPs.",
+		msgEmptyFounders:       "%s does not have any founders.",
+		msgEmptyList:           "The list for %s is empty.",
+		msgRateLimited:         "The data service is busy right now. Please try again in a minute.",
+		msgUnavailable:         "The data service is temporarily unavailable. Please try again later.",
+		msgTimeout:             "The request took too long. Please try again.",
+		msgFetchFailed:         "Failed to fetch data for %s.",
+		msgRetryButton:         "Retry",
+		msgChannelPrompt:       "Enter the channel name:",
+		msgHistoryDisabled:     "Change history is not enabled on this bot.",
+		msgNoHistory:           "There is no stored snapshot of %s yet. History is recorded on every lookup.",
+		msgInvalidChanges:      "Could not understand \"%s\". Use: <channel> [follows|mods|vips|founders] [period], e.g. \"xqc mods 7d\".",
+		msgWatchUsage:          "Usage: /watch <channel> [mods] [vips] [founders], e.g. /watch xqc mods vips",
+		msgWatchLimit:          "This chat already watches %d channels. Remove one with /unwatch first.",
+		msgWatchAdded:          "Watching %s (%s). You will be notified when someone is added or removed.",
+		msgUnwatchUsage:        "Usage: /unwatch <channel>",
+		msgNotWatching:         "This chat does not watch %s.",
+		msgUnwatched:           "Stopped watching %s.",
+		msgWatchlistEmpty:      "This chat does not watch any channels. Add one with /watch <channel>.",
+		msgWatchlistHeader:     "Watched channels:",
+		msgGoLiveUsage:         "Usage: /golive <your Twitch login> to get notified when channels you follow go live, /golive off to stop.",
+		msgGoLiveNotRegistered: "Go-live notifications are not enabled in this chat. Enable them with /golive <your Twitch login>.",
+		msgGoLiveDisabled:      "Go-live notifications disabled.",
+		msgGoLiveEnabled:       "You will be notified when channels followed by %s go live.",
+		msgGoLiveStatus:        "Go-live notifications for channels followed by %s.",
+		msgMuteUsage:           "Usage: /mute <channel>",
+		msgMuted:               "Muted %s.",
+		msgUnmuteUsage:         "Usage: /unmute <channel>",
+		msgUnmuted:             "Unmuted %s.",
+		msgMutedList:           "Muted: %s",
+		msgQuietUsage:          "Usage: /quiet <from>-<to> [UTC offset], e.g. /quiet 23-8 +3, or /quiet off",
+		msgQuietEnabled:        "Quiet hours: %02d:00-%02d:00 %s.",
+		msgQuietDisabled:       "Quiet hours disabled.",
+		msgFlowExpired:         "This dialog has expired, please start again.",
+		msgFlowChooseOption:    "Please choose one of the options above.",
+		msgFlowEmptyInput:      "The answer cannot be empty.",
+		msgFlowExpectNumber:    "Please enter a number.",
+		msgFlowExpectText:      "Please type your answer as a message.",
+		msgChangesListPrompt:   "Select the list type:",
+		msgChangesPeriodPrompt: "Select the period or type one, e.g. \"2w\":",
+		msgInvalidPeriod:       "Invalid period \"%s\", use values like \"7d\", \"2w\" or \"12h\".",
+		msgInvalidChannel:      "\"%s\" is not a valid Twitch channel name.",
+		msgUsernameEmpty:       "Please enter a channel name.",
+		msgUsernameLength:      "\"%s\" is not a valid Twitch name: it must be %d-%d characters long.",
+		msgUsernameChars:       "\"%s\" is not a valid Twitch name: only Latin letters, digits and underscores are allowed.",
+		msgNotChannelURL:       "The link %s does not point to a Twitch channel.",
+		msgBatchTooLarge:       "Up to %d channels can be looked up at once.",
+		msgBatchSummary:        "Fetched %d of %d channels.",
+		msgOverlapUsage:        "Usage: /overlap <channel>, <channel>[, ...] [mods] [vips]\nExample: /overlap xqc, shroud, summit1g",
+		msgOverlapTooFew:       "At least two channels are needed to compare.",
+		msgMutualUsage:         "Usage: /mutual <user> <user>\nExample: /mutual xqc shroud",
+		msgNoMatches:           "No entries of %s match the selected filters.",
+		msgInvalidFlag:         "Unknown or invalid option %s. Available: --sort=date|name|live (prefix \"-\" to reverse), --banned, --live, --subscribed, --from=YYYY-MM-DD, --to=YYYY-MM-DD, --since=7d, --contains=text.",
+		msgUnsupportedFlag:     "Option %s is not available for this list.",
+		msgSortOldest:          "Oldest",
+		msgSortNewest:          "Newest",
+		msgSortName:            "A–Z",
+		msgSortLive:            "Live first",
+		msgFilterLive:          "Live only",
+		msgFilterBanned:        "Banned only",
+		msgFilterSubscribed:    "Subscribed only",
+		msgQueryReset:          "Reset",
+		msgPageExpired:         "This result has expired, please request it again.",
+		msgExportUsage:         "Usage: /export <list> <channel> [csv|json|xlsx] [options]\nLists: follows, mods, vips, founders\nExample: /export mods xqc xlsx --banned",
+		msgExportButton:        "⬇️ %s",
+		msgExportCaption:       "%s of %s: %d entries",
+		msgInternalError:       "Something went wrong while processing your request. Please try again later.",
+		msgCooldown:            "You are sending requests too quickly. Please try again in %s.",
+		msgQuotaExceeded:       "You have reached the daily limit of %d requests. It resets in %s.",
+		msgAdminOnly:           "This command is only available to administrators.",
+		msgAccessUsage:         "Usage: /access [allow|deny|remove <user ID>]\nWithout arguments the allowlist and denylist are shown.",
+		msgAccessAllowed:       "User %d is allowlisted and no longer rate limited.",
+		msgAccessDenied:        "User %d is denylisted and will be ignored by the bot.",
+		msgAccessRemoved:       "User %d was removed from the access lists.",
+		msgAccessNotListed:     "User %d is not on any access list.",
+		msgAccessList:          "Allowlist: %s\nDenylist: %s",
+	},
+	"ru": {
+		msgUserNotFound:        "Канал %s не найден на Twitch.",
+		msgEmptyFollows:        "%s ни на кого не подписан.",
+		msgEmptyMods:           "У %s нет модераторов.",
+		msgEmptyVips:           "У %s нет VIP-пользователей.",
+		msgEmptyFounders:       "У %s нет основателей.",
+		msgEmptyList:           "Список для %s пуст.",
+		msgRateLimited:         "Сервис данных сейчас перегружен. Попробуйте через минуту.",
+		msgUnavailable:         "Сервис данных временно недоступен. Попробуйте позже.",
+		msgTimeout:             "Запрос выполнялся слишком долго. Попробуйте ещё раз.",
+		msgFetchFailed:         "Не удалось получить данные для %s.",
+		msgRetryButton:         "Повторить",
+		msgChannelPrompt:       "Введите название канала:",
+		msgHistoryDisabled:     "История изменений в этом боте не включена.",
+		msgNoHistory:           "Пока нет сохранённых снимков %s. История записывается при каждом запросе.",
+		msgInvalidChanges:      "Не удалось разобрать \"%s\". Формат: <канал> [follows|mods|vips|founders] [период], например \"xqc mods 7d\".",
+		msgWatchUsage:          "Использование: /watch <канал> [mods] [vips] [founders], например /watch xqc mods vips",
+		msgWatchLimit:          "Этот чат уже отслеживает %d каналов. Сначала удалите один через /unwatch.",
+		msgWatchAdded:          "Отслеживаю %s (%s). Вы получите уведомление, когда кого-то добавят или удалят.",
+		msgUnwatchUsage:        "Использование: /unwatch <канал>",
+		msgNotWatching:         "Этот чат не отслеживает %s.",
+		msgUnwatched:           "Больше не отслеживаю %s.",
+		msgWatchlistEmpty:      "Этот чат не отслеживает ни одного канала. Добавьте канал через /watch <канал>.",
+		msgWatchlistHeader:     "Отслеживаемые каналы:",
+		msgGoLiveUsage:         "Использование: /golive <ваш логин Twitch>, чтобы получать уведомления о начале трансляций каналов, на которые вы подписаны, /golive off, чтобы отключить.",
+		msgGoLiveNotRegistered: "Уведомления о трансляциях в этом чате не включены. Включите их через /golive <ваш логин Twitch>.",
+		msgGoLiveDisabled:      "Уведомления о трансляциях отключены.",
+		msgGoLiveEnabled:       "Вы получите уведомление, когда каналы, на которые подписан %s, начнут трансляцию.",
+		msgGoLiveStatus:        "Уведомления о трансляциях каналов, на которые подписан %s.",
+		msgMuteUsage:           "Использование: /mute <канал>",
+		msgMuted:               "Канал %s заглушён.",
+		msgUnmuteUsage:         "Использование: /unmute <канал>",
+		msgUnmuted:             "Канал %s больше не заглушён.",
+		msgMutedList:           "Заглушены: %s",
+		msgQuietUsage:          "Использование: /quiet <с>-<до> [смещение UTC], например /quiet 23-8 +3, или /quiet off",
+		msgQuietEnabled:        "Тихие часы: %02d:00-%02d:00 %s.",
+		msgQuietDisabled:       "Тихие часы отключены.",
+		msgFlowExpired:         "Диалог устарел, начните заново.",
+		msgFlowChooseOption:    "Выберите один из вариантов выше.",
+		msgFlowEmptyInput:      "Ответ не может быть пустым.",
+		msgFlowExpectNumber:    "Введите число.",
+		msgFlowExpectText:      "Отправьте ответ сообщением.",
+		msgChangesListPrompt:   "Выберите тип списка:",
+		msgChangesPeriodPrompt: "Выберите период или введите свой, например \"2w\":",
+		msgInvalidPeriod:       "Неверный период \"%s\", используйте значения вроде \"7d\", \"2w\" или \"12h\".",
+		msgInvalidChannel:      "\"%s\" — недопустимое имя канала Twitch.",
+		msgUsernameEmpty:       "Введите название канала.",
+		msgUsernameLength:      "\"%s\" — недопустимое имя Twitch: длина должна быть от %d до %d символов.",
+		msgUsernameChars:       "\"%s\" — недопустимое имя Twitch: разрешены только латинские буквы, цифры и подчёркивания.",
+		msgNotChannelURL:       "Ссылка %s не ведёт на канал Twitch.",
+		msgBatchTooLarge:       "За раз можно запросить не более %d каналов.",
+		msgBatchSummary:        "Получено %d из %d каналов.",
+		msgOverlapUsage:        "Использование: /overlap <канал>, <канал>[, ...] [mods] [vips]\nПример: /overlap xqc, shroud, summit1g",
+		msgOverlapTooFew:       "Для сравнения нужно как минимум два канала.",
+		msgMutualUsage:         "Использование: /mutual <пользователь> <пользователь>\nПример: /mutual xqc shroud",
+		msgNoMatches:           "Нет записей %s, подходящих под выбранные фильтры.",
+		msgInvalidFlag:         "Неизвестный или неверный параметр %s. Доступны: --sort=date|name|live (префикс \"-\" для обратного порядка), --banned, --live, --subscribed, --from=ГГГГ-ММ-ДД, --to=ГГГГ-ММ-ДД, --since=7d, --contains=текст.",
+		msgUnsupportedFlag:     "Параметр %s недоступен для этого списка.",
+		msgSortOldest:          "Сначала старые",
+		msgSortNewest:          "Сначала новые",
+		msgSortName:            "А–Я",
+		msgSortLive:            "Сначала в эфире",
+		msgFilterLive:          "Только в эфире",
+		msgFilterBanned:        "Только забаненные",
+		msgFilterSubscribed:    "Только подписанные",
+		msgQueryReset:          "Сбросить",
+		msgPageExpired:         "Этот результат устарел, запросите его заново.",
+		msgExportUsage:         "Использование: /export <список> <канал> [csv|json|xlsx] [параметры]\nСписки: follows, mods, vips, founders\nПример: /export mods xqc xlsx --banned",
+		msgExportButton:        "⬇️ %s",
+		msgExportCaption:       "%s канала %s: записей — %d",
+		msgInternalError:       "Что-то пошло не так при обработке запроса. Попробуйте позже.",
+		msgCooldown:            "Вы отправляете запросы слишком часто. Попробуйте снова через %s.",
+		msgQuotaExceeded:       "Вы исчерпали дневной лимит в %d запросов. Он обновится через %s.",
+		msgAdminOnly:           "Эта команда доступна только администраторам.",
+		msgAccessUsage:         "Использование: /access [allow|deny|remove <ID пользователя>]\nБез аргументов показывает списки разрешённых и заблокированных.",
+		msgAccessAllowed:       "Пользователь %d добавлен в список разрешённых, ограничения на него не действуют.",
+		msgAccessDenied:        "Пользователь %d заблокирован, бот будет его игнорировать.",
+		msgAccessRemoved:       "Пользователь %d удалён из списков доступа.",
+		msgAccessNotListed:     "Пользователя %d нет в списках доступа.",
+		msgAccessList:          "Разрешённые: %s\nЗаблокированные: %s",
+	},
+	"uk": {
+		msgUserNotFound:        "Канал %s не знайдено на Twitch.",
+		msgEmptyFollows:        "%s ні на кого не підписаний.",
+		msgEmptyMods:           "У %s немає модераторів.",
+		msgEmptyVips:           "У %s немає VIP-користувачів.",
+		msgEmptyFounders:       "У %s немає засновників.",
+		msgEmptyList:           "Список для %s порожній.",
+		msgRateLimited:         "Сервіс даних зараз перевантажений. Спробуйте за хвилину.",
+		msgUnavailable:         "Сервіс даних тимчасово недоступний. Спробуйте пізніше.",
+		msgTimeout:             "Запит виконувався надто довго. Спробуйте ще раз.",
+		msgFetchFailed:         "Не вдалося отримати дані для %s.",
+		msgRetryButton:         "Повторити",
+		msgChannelPrompt:       "Введіть назву каналу:",
+		msgHistoryDisabled:     "Історію змін у цьому боті не ввімкнено.",
+		msgNoHistory:           "Поки немає збережених знімків %s. Історія записується під час кожного запиту.",
+		msgInvalidChanges:      "Не вдалося розібрати \"%s\". Формат: <канал> [follows|mods|vips|founders] [період], наприклад \"xqc mods 7d\".",
+		msgWatchUsage:          "Використання: /watch <канал> [mods] [vips] [founders], наприклад /watch xqc mods vips",
+		msgWatchLimit:          "Цей чат уже відстежує %d каналів. Спершу видаліть один через /unwatch.",
+		msgWatchAdded:          "Відстежую %s (%s). Ви отримаєте сповіщення, коли когось додадуть або видалять.",
+		msgUnwatchUsage:        "Використання: /unwatch <канал>",
+		msgNotWatching:         "Цей чат не відстежує %s.",
+		msgUnwatched:           "Більше не відстежую %s.",
+		msgWatchlistEmpty:      "Цей чат не відстежує жодного каналу. Додайте канал через /watch <канал>.",
+		msgWatchlistHeader:     "Відстежувані канали:",
+		msgGoLiveUsage:         "Використання: /golive <ваш логін Twitch>, щоб отримувати сповіщення про початок трансляцій каналів, на які ви підписані, /golive off, щоб вимкнути.",
+		msgGoLiveNotRegistered: "Сповіщення про трансляції в цьому чаті не ввімкнено. Увімкніть їх через /golive <ваш логін Twitch>.",
+		msgGoLiveDisabled:      "Сповіщення про трансляції вимкнено.",
+		msgGoLiveEnabled:       "Ви отримаєте сповіщення, коли канали, на які підписаний %s, почнуть трансляцію.",
+		msgGoLiveStatus:        "Сповіщення про трансляції каналів, на які підписаний %s.",
+		msgMuteUsage:           "Використання: /mute <канал>",
+		msgMuted:               "Канал %s приглушено.",
+		msgUnmuteUsage:         "Використання: /unmute <канал>",
+		msgUnmuted:             "Канал %s більше не приглушено.",
+		msgMutedList:           "Приглушені: %s",
+		msgQuietUsage:          "Використання: /quiet <з>-<до> [зсув UTC], наприклад /quiet 23-8 +3, або /quiet off",
+		msgQuietEnabled:        "Тихі години: %02d:00-%02d:00 %s.",
+		msgQuietDisabled:       "Тихі години вимкнено.",
+		msgFlowExpired:         "Діалог застарів, почніть знову.",
+		msgFlowChooseOption:    "Оберіть один із варіантів вище.",
+		msgFlowEmptyInput:      "Відповідь не може бути порожньою.",
+		msgFlowExpectNumber:    "Введіть число.",
+		msgFlowExpectText:      "Надішліть відповідь повідомленням.",
+		msgChangesListPrompt:   "Оберіть тип списку:",
+		msgChangesPeriodPrompt: "Оберіть період або введіть свій, наприклад \"2w\":",
+		msgInvalidPeriod:       "Неправильний період \"%s\", використовуйте значення на зразок \"7d\", \"2w\" або \"12h\".",
+		msgInvalidChannel:      "\"%s\" — неприпустиме ім'я каналу Twitch.",
+		msgUsernameEmpty:       "Введіть назву каналу.",
+		msgUsernameLength:      "\"%s\" — неприпустиме ім'я Twitch: довжина має бути від %d до %d символів.",
+		msgUsernameChars:       "\"%s\" — неприпустиме ім'я Twitch: дозволені лише латинські літери, цифри та підкреслення.",
+		msgNotChannelURL:       "Посилання %s не веде на канал Twitch.",
+		msgBatchTooLarge:       "За раз можна запитати не більше %d каналів.",
+		msgBatchSummary:        "Отримано %d із %d каналів.",
+		msgOverlapUsage:        "Використання: /overlap <канал>, <канал>[, ...] [mods] [vips]\nПриклад: /overlap xqc, shroud, summit1g",
+		msgOverlapTooFew:       "Для порівняння потрібно щонайменше два канали.",
+		msgMutualUsage:         "Використання: /mutual <користувач> <користувач>\nПриклад: /mutual xqc shroud",
+		msgNoMatches:           "Немає записів %s, що відповідають вибраним фільтрам.",
+		msgInvalidFlag:         "Невідомий або неправильний параметр %s. Доступні: --sort=date|name|live (префікс \"-\" для зворотного порядку), --banned, --live, --subscribed, --from=РРРР-ММ-ДД, --to=РРРР-ММ-ДД, --since=7d, --contains=текст.",
+		msgUnsupportedFlag:     "Параметр %s недоступний для цього списку.",
+		msgSortOldest:          "Спочатку старі",
+		msgSortNewest:          "Спочатку нові",
+		msgSortName:            "А–Я",
+		msgSortLive:            "Спочатку в ефірі",
+		msgFilterLive:          "Лише в ефірі",
+		msgFilterBanned:        "Лише забанені",
+		msgFilterSubscribed:    "Лише підписані",
+		msgQueryReset:          "Скинути",
+		msgPageExpired:         "Цей результат застарів, запитайте його знову.",
+		msgExportUsage:         "Використання: /export <список> <канал> [csv|json|xlsx] [параметри]\nСписки: follows, mods, vips, founders\nПриклад: /export mods xqc xlsx --banned",
+		msgExportButton:        "⬇️ %s",
+		msgExportCaption:       "%s каналу %s: записів — %d",
+		msgInternalError:       "Під час обробки запиту щось пішло не так. Спробуйте пізніше.",
+		msgCooldown:            "Ви надсилаєте запити занадто часто. Спробуйте знову через %s.",
+		msgQuotaExceeded:       "Ви вичерпали денний ліміт у %d запитів. Він оновиться через %s.",
+		msgAdminOnly:           "Ця команда доступна лише адміністраторам.",
+		msgAccessUsage:         "Використання: /access [allow|deny|remove <ID користувача>]\nБез аргументів показує списки дозволених і заблокованих.",
+		msgAccessAllowed:       "Користувача %d додано до списку дозволених, обмеження на нього не діють.",
+		msgAccessDenied:        "Користувача %d заблоковано, бот його ігноруватиме.",
+		msgAccessRemoved:       "Користувача %d видалено зі списків доступу.",
+		msgAccessNotListed:     "Користувача %d немає в списках доступу.",
+		msgAccessList:          "Дозволені: %s\nЗаблоковані: %s",
+	},
+}
+
+// localize returns the message for key in the given language, falling back to English.
+//
+// Parameters:
+//
+//	lang - IETF language tag reported by Telegram (e.g., "en", "uk", "pt-br")
+//	key - Message key
+//	args - Optional format arguments
+//
+// Returns:
+//
+//	The localized, formatted message
+func localize(lang string, key messageKey, args ...any) string {
+	lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+
+	messages, ok := catalog[lang]
+	if !ok {
+		messages = catalog[defaultLanguage]
+	}
+
+	format, ok := messages[key]
+	if !ok {
+		format = catalog[defaultLanguage][key]
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// messageLang returns the language of a message's sender.
+//
+// Parameters:
+//
+//	msg - Telegram message
+//
+// Returns:
+//
+//	The sender's language code, empty if the message has no sender (e.g., a channel post)
+func messageLang(msg *tgbotapi.Message) string {
+	if msg.From == nil {
+		return ""
+	}
+	return msg.From.LanguageCode
+}
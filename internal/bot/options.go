@@ -0,0 +1,308 @@
+package bot
+
+import "time"
+
+// Option configures optional Bot features.
+type Option func(*Bot)
+
+// WithWorkers sets how many updates are processed concurrently and how many may be queued.
+//
+// Parameters:
+//
+//	workers - Number of worker goroutines
+//	queueSize - Maximum number of queued and in-flight updates
+//
+// Returns:
+//
+//	An Option that applies the worker pool size
+func WithWorkers(workers, queueSize int) Option {
+	return func(b *Bot) {
+		if workers > 0 {
+			b.workers = workers
+		}
+		if queueSize > 0 {
+			b.queueSize = queueSize
+		}
+	}
+}
+
+// WithBatchLimits limits lookups of several channels in one message.
+//
+// Parameters:
+//
+//	size - Maximum number of channels in one lookup
+//	concurrency - Number of channels fetched at the same time
+//
+// Returns:
+//
+//	An Option that applies the batch limits
+func WithBatchLimits(size, concurrency int) Option {
+	return func(b *Bot) {
+		if size > 0 {
+			b.batchSize = size
+		}
+		if concurrency > 0 {
+			b.batchConcurrency = concurrency
+		}
+	}
+}
+
+// WithPagination configures how long paginated results can be browsed.
+//
+// Parameters:
+//
+//	ttl - Time a result can be browsed after it was sent
+//	maxResults - Maximum number of results kept in memory
+//
+// Returns:
+//
+//	An Option that applies the pagination limits
+func WithPagination(ttl time.Duration, maxResults int) Option {
+	return func(b *Bot) {
+		if ttl > 0 && maxResults > 0 {
+			b.pages = newPageStore(ttl, maxResults)
+		}
+	}
+}
+
+// WithStateStore replaces the default in-memory conversation state store.
+//
+// Parameters:
+//
+//	states - State store to use
+//
+// Returns:
+//
+//	An Option that applies the state store
+func WithStateStore(states StateStore) Option {
+	return func(b *Bot) {
+		b.states = states
+	}
+}
+
+// WithHistory enables change tracking backed by stored list snapshots.
+//
+// Parameters:
+//
+//	history - Snapshot history to compare current lists against
+//
+// Returns:
+//
+//	An Option that applies the history
+func WithHistory(history History) Option {
+	return func(b *Bot) {
+		b.history = history
+	}
+}
+
+// WithWatchlist enables /watch subscriptions polled on a schedule.
+//
+// Parameters:
+//
+//	subs - Persisted subscription store
+//	interval - Time between polls of every watched channel list
+//	maxPerChat - Maximum number of watched channels per chat, zero for unlimited
+//
+// Returns:
+//
+//	An Option that applies the watchlist
+func WithWatchlist(subs SubscriptionStore, interval time.Duration, maxPerChat int) Option {
+	return func(b *Bot) {
+		b.watcher = &watcher{
+			subs:     subs,
+			interval: interval,
+			maxSubs:  maxPerChat,
+			lastSeen: make(map[watchKey]any),
+		}
+	}
+}
+
+// WithPollFetcher sets the fetcher used by watchlist and, unless WithLiveFetcher
+// is given, go-live polls. It should not serve cached results, or changes made
+// shortly after a poll, such as a channel going live, are reported a cycle late.
+// By default the bot's fetcher is used.
+//
+// Parameters:
+//
+//	f - Fetcher that always calls the upstream API
+//
+// Returns:
+//
+//	An Option that applies the poll fetcher
+func WithPollFetcher(f Fetcher) Option {
+	return func(b *Bot) {
+		b.pollFetch = f
+	}
+}
+
+// WithLiveFetcher sets the fetcher used by go-live polls. Liveness needs no
+// history, so it need not record snapshots either. By default the poll fetcher
+// is used.
+//
+// Parameters:
+//
+//	f - Fetcher that always calls the upstream API
+//
+// Returns:
+//
+//	An Option that applies the go-live fetcher
+func WithLiveFetcher(f Fetcher) Option {
+	return func(b *Bot) {
+		b.liveFetch = f
+	}
+}
+
+// WithLiveNotifications enables go-live notifications for registered Twitch logins.
+//
+// Parameters:
+//
+//	subs - Persisted go-live registrations
+//	interval - Time between polls of every registered login's follows
+//	maxPerHour - Maximum notifications per chat per hour, zero for unlimited
+//
+// Returns:
+//
+//	An Option that applies the go-live notifier
+func WithLiveNotifications(subs LiveStore, interval time.Duration, maxPerHour int) Option {
+	return func(b *Bot) {
+		b.live = &liveNotifier{
+			subs:       subs,
+			interval:   interval,
+			maxPerHour: maxPerHour,
+			wasLive:    make(map[string]map[string]bool),
+			sent:       make(map[int64][]time.Time),
+		}
+	}
+}
+
+// WithWebhook receives updates through a webhook served by an embedded HTTP server
+// instead of long polling.
+//
+// Parameters:
+//
+//	cfg - Webhook configuration
+//
+// Returns:
+//
+//	An Option that enables the webhook
+func WithWebhook(cfg WebhookConfig) Option {
+	return func(b *Bot) {
+		if cfg.URL != "" {
+			b.webhook = &cfg
+		}
+	}
+}
+
+// WithShutdownTimeout sets how long in-flight work may take to finish on shutdown.
+//
+// Parameters:
+//
+//	timeout - Time limit for finishing queued and in-flight updates and background pollers
+//
+// Returns:
+//
+//	An Option that applies the shutdown timeout
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(b *Bot) {
+		if timeout > 0 {
+			b.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithCrashReporter sets a function notified of recovered panics.
+//
+// Parameters:
+//
+//	reporter - Crash reporter
+//
+// Returns:
+//
+//	An Option that applies the crash reporter
+func WithCrashReporter(reporter CrashReporter) Option {
+	return func(b *Bot) {
+		b.crashReporter = reporter
+	}
+}
+
+// WithCrashReportChat posts reports of recovered panics to a Telegram chat,
+// e.g. a private chat with the bot's administrator. Reports are sent through
+// the outgoing message queue and limited to a few per minute, so a burst of
+// panics cannot flood the chat.
+//
+// Parameters:
+//
+//	chatID - Chat to post the reports to
+//
+// Returns:
+//
+//	An Option that applies the crash reporter
+func WithCrashReportChat(chatID int64) Option {
+	return func(b *Bot) {
+		b.crashReporter = b.chatCrashReporter(chatID)
+	}
+}
+
+// WithSendLimits sets the rate limits of outgoing messages. Zero fields keep their defaults.
+//
+// Parameters:
+//
+//	limits - Rate limits of outgoing messages
+//
+// Returns:
+//
+//	An Option that applies the send limits
+func WithSendLimits(limits SendLimits) Option {
+	return func(b *Bot) {
+		if limits.GlobalPerSecond > 0 {
+			b.sendLimits.GlobalPerSecond = limits.GlobalPerSecond
+		}
+		if limits.ChatInterval > 0 {
+			b.sendLimits.ChatInterval = limits.ChatInterval
+		}
+		if limits.GroupPerMinute > 0 {
+			b.sendLimits.GroupPerMinute = limits.GroupPerMinute
+		}
+		if limits.MaxAttempts > 0 {
+			b.sendLimits.MaxAttempts = limits.MaxAttempts
+		}
+	}
+}
+
+// WithRateLimits limits how many requests users and chats may send. Commands,
+// flow answers and button presses count; page navigation and commands
+// registered WithoutRateLimit do not.
+//
+// Parameters:
+//
+//	limits - Token bucket sizes and refill intervals and the daily quota
+//
+// Returns:
+//
+//	An Option that enables rate limiting
+func WithRateLimits(limits RateLimits) Option {
+	return func(b *Bot) {
+		b.limiter = newLimiter(limits)
+	}
+}
+
+// WithAccessControl sets the administrators and the store of the allowlist and denylist.
+// Administrators are never rate limited and may change the lists with /access.
+//
+// Parameters:
+//
+//	access - Store of the allowlist and denylist
+//	admins - Telegram user IDs of administrators
+//
+// Returns:
+//
+//	An Option that applies the access control
+func WithAccessControl(access AccessStore, admins []int64) Option {
+	return func(b *Bot) {
+		b.access = access
+		b.admins = make(map[int64]bool, len(admins))
+		for _, id := range admins {
+			b.admins[id] = true
+		}
+	}
+}
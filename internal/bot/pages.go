@@ -0,0 +1,272 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+const (
+	defaultPageTTL         = time.Hour // Default time a paginated result can be browsed
+	defaultMaxPagedResults = 1000      // Default number of paginated results kept in memory
+	pageCallbackPrefix     = "p:"      // Callback data prefix of page navigation buttons
+	pageTokenBytes         = 6         // Random bytes in a page token, 8 characters encoded
+)
+
+// pagedResult is a long response split into pages, browsed by editing one message.
+type pagedResult struct {
+	pages     []string                          // Message texts, one per page
+	extra     [][]tgbotapi.InlineKeyboardButton // Buttons shown under every page
+	current   int                               // Page currently shown
+	expiresAt time.Time
+}
+
+// pageStore keeps paginated results in memory under short random tokens,
+// so navigation buttons fit into Telegram's callback data limit.
+type pageStore struct {
+	ttl        time.Duration
+	maxResults int
+	mu         sync.Mutex
+	results    map[string]*pagedResult
+}
+
+// newPageStore creates an empty page store.
+//
+// Parameters:
+//
+//	ttl - Time a result can be browsed after it was sent
+//	maxResults - Maximum number of stored results; the ones expiring first are evicted
+//
+// Returns:
+//
+//	A pointer to a new pageStore instance
+func newPageStore(ttl time.Duration, maxResults int) *pageStore {
+	return &pageStore{
+		ttl:        ttl,
+		maxResults: maxResults,
+		results:    make(map[string]*pagedResult),
+	}
+}
+
+// put stores a result and returns its token.
+//
+// Parameters:
+//
+//	result - Result to store
+//
+// Returns:
+//
+//	The opaque token of the result and an error if no token could be generated
+func (s *pageStore) put(result *pagedResult) (string, error) {
+	buf := make([]byte, pageTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("bot.pageStore.put: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for t, r := range s.results {
+		if now.After(r.expiresAt) {
+			delete(s.results, t)
+		}
+	}
+
+	for s.maxResults > 0 && len(s.results) >= s.maxResults {
+		var oldest string
+		for t, r := range s.results {
+			if oldest == "" || r.expiresAt.Before(s.results[oldest].expiresAt) {
+				oldest = t
+			}
+		}
+		delete(s.results, oldest)
+	}
+
+	result.expiresAt = now.Add(s.ttl)
+	s.results[token] = result
+	return token, nil
+}
+
+// show switches a result to a page.
+//
+// Parameters:
+//
+//	token - Token of the result
+//	page - Zero-based page to show
+//
+// Returns:
+//
+//	A copy of the result, whether the page changed and false if the token is unknown or expired
+func (s *pageStore) show(token string, page int) (pagedResult, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, ok := s.results[token]
+	if !ok || time.Now().After(result.expiresAt) {
+		delete(s.results, token)
+		return pagedResult{}, false, false
+	}
+
+	page = min(max(page, 0), len(result.pages)-1)
+	changed := page != result.current
+	result.current = page
+
+	return *result, changed, true
+}
+
+// sendPaged sends a long HTML response as a single message. When it does not
+// fit into one message it is split into pages browsed with navigation buttons.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	chatID - Telegram chat ID to reply to
+//	messageID - Message to replace, zero to send a new one
+//	text - HTML response
+//	extra - Buttons shown under every page
+func (b *Bot) sendPaged(ctx context.Context, chatID int64, messageID int, text string, extra [][]tgbotapi.InlineKeyboardButton) {
+	const op = "bot.sendPaged"
+
+	result := &pagedResult{
+		pages: utils.SplitMessage(text, telegramMessageLimit),
+		extra: extra,
+	}
+	if len(result.pages) == 0 {
+		return
+	}
+
+	var token string
+	if len(result.pages) > 1 {
+		var err error
+		if token, err = b.pages.put(result); err != nil {
+			log.Printf("%s: %v", op, err)
+		}
+	}
+
+	if err := b.showPage(ctx, chatID, messageID, token, *result); err != nil {
+		log.Printf("%s: %v", op, err)
+	}
+}
+
+// showPage sends or edits a message to show the current page of a result.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	chatID - Telegram chat ID
+//	messageID - Message to edit, zero to send a new one
+//	token - Token of the stored result, empty if it has a single page
+//	result - Result to show
+//
+// Returns:
+//
+//	An error if the message could not be sent
+func (b *Bot) showPage(ctx context.Context, chatID int64, messageID int, token string, result pagedResult) error {
+	rows := result.extra
+	if token != "" {
+		rows = append([][]tgbotapi.InlineKeyboardButton{pageNavigation(token, result.current, len(result.pages))}, rows...)
+	}
+
+	var markup *tgbotapi.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		markup = &keyboard
+	}
+
+	text := result.pages[result.current]
+
+	if messageID == 0 {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = tgbotapi.ModeHTML
+		msg.DisableWebPagePreview = true
+		if markup != nil {
+			msg.ReplyMarkup = *markup
+		}
+		if _, err := b.send(ctx, chatID, msg); err != nil {
+			return fmt.Errorf("failed to send page: %w", err)
+		}
+		return nil
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = markup
+	if _, err := b.send(ctx, chatID, edit); err != nil {
+		return fmt.Errorf("failed to edit page: %w", err)
+	}
+	return nil
+}
+
+// pageNavigation builds the First/Prev/position/Next/Last button row.
+//
+// Parameters:
+//
+//	token - Token of the stored result
+//	current - Zero-based page currently shown
+//	total - Number of pages
+//
+// Returns:
+//
+//	The navigation buttons
+func pageNavigation(token string, current, total int) []tgbotapi.InlineKeyboardButton {
+	button := func(label string, page int) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(label, pageCallbackPrefix+token+":"+strconv.Itoa(page))
+	}
+
+	return tgbotapi.NewInlineKeyboardRow(
+		button("⏮", 0),
+		button("◀", max(current-1, 0)),
+		button(fmt.Sprintf("%d/%d", current+1, total), current),
+		button("▶", min(current+1, total-1)),
+		button("⏭", total-1),
+	)
+}
+
+// handlePageCallback shows the page requested by a navigation button.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	callback - Callback query from Telegram
+//	data - Callback data without its prefix
+func (b *Bot) handlePageCallback(ctx context.Context, callback *tgbotapi.CallbackQuery, data string) {
+	const op = "bot.handlePageCallback"
+
+	chatID := callback.Message.Chat.ID
+
+	token, pageText, _ := strings.Cut(data, ":")
+	page, err := strconv.Atoi(pageText)
+	if err != nil {
+		log.Printf("%s: invalid callback data %q", op, data)
+		return
+	}
+
+	result, changed, ok := b.pages.show(token, page)
+	if !ok {
+		if err := b.sendText(ctx, chatID, localize(callback.From.LanguageCode, msgPageExpired)); err != nil {
+			log.Printf("%s: %v", op, err)
+		}
+		return
+	}
+
+	// Editing a message to identical content is rejected by Telegram.
+	if !changed {
+		return
+	}
+
+	if err := b.showPage(ctx, chatID, callback.Message.MessageID, token, result); err != nil {
+		log.Printf("%s: %v", op, err)
+	}
+}
@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	maxCrashReportInput      = 200  // Number of input bytes included in a crash report message
+	maxCrashReportStack      = 3000 // Number of stack trace bytes included in a crash report message
+	maxCrashReportsPerMinute = 5    // Number of crash reports posted to a chat per minute
+)
+
+// errPanicked is wrapped by the errors of tasks that panicked.
+var errPanicked = errors.New("task panicked")
+
+// updateContextKey is the context key of the update being handled.
+type updateContextKey struct{}
+
+// CrashReport describes a panic recovered while handling an update.
+type CrashReport struct {
+	UpdateID int    // Telegram update ID
+	Kind     string // Update kind: "message", "callback" or "other", or "background" without an update
+	Task     string // Fan-out or background task that panicked, empty for the update handler
+	ChatID   int64  // Chat the update came from, zero if unknown
+	UserID   int64  // User who sent the update, zero if unknown
+	Input    string // Message text or callback data
+	Panic    any    // Value passed to panic
+	Stack    []byte // Stack trace of the panicking goroutine
+}
+
+// where describes the code that panicked.
+func (r CrashReport) where() string {
+	if r.Task != "" {
+		return r.Task
+	}
+	return "update handler"
+}
+
+// CrashReporter receives reports of recovered panics, e.g. to forward them to an admin chat.
+// It is called synchronously from the goroutine that panicked.
+type CrashReporter func(report CrashReport)
+
+// newCrashReport collects the metadata of an update that caused a panic.
+//
+// Parameters:
+//
+//	update - Update being handled
+//	p - Value passed to panic
+//	stack - Stack trace of the panicking goroutine
+//
+// Returns:
+//
+//	The crash report
+func newCrashReport(update tgbotapi.Update, p any, stack []byte) CrashReport {
+	report := CrashReport{
+		UpdateID: update.UpdateID,
+		Kind:     "other",
+		ChatID:   updateChatID(update),
+		Panic:    p,
+		Stack:    stack,
+	}
+
+	switch {
+	case update.Message != nil:
+		report.Kind = "message"
+		report.Input = update.Message.Text
+	case update.CallbackQuery != nil:
+		report.Kind = "callback"
+		report.Input = update.CallbackQuery.Data
+	}
+
+	if user := update.SentFrom(); user != nil {
+		report.UserID = user.ID
+	}
+
+	return report
+}
+
+// recoverUpdate handles a panic recovered while handling an update. The panic
+// is reported and the user is told something went wrong. Other updates keep
+// being served.
+//
+// Parameters:
+//
+//	ctx - Context that aborts sending
+//	update - Update being handled
+//	p - Value passed to panic
+func (b *Bot) recoverUpdate(ctx context.Context, update tgbotapi.Update, p any) {
+	const op = "bot.recoverUpdate"
+
+	report := newCrashReport(update, p, debug.Stack())
+	b.reportPanic(report)
+
+	if report.ChatID != 0 {
+		lang := ""
+		if user := update.SentFrom(); user != nil {
+			lang = user.LanguageCode
+		}
+		if err := b.sendText(ctx, report.ChatID, localize(lang, msgInternalError)); err != nil {
+			log.Printf("%s: %v", op, err)
+		}
+	}
+}
+
+// guard runs a task on a goroutine other than the update handler's, such as a
+// fan-out worker or a background poll, and reports a panic instead of letting
+// it crash the bot. The update being handled, if any, is taken from ctx.
+//
+// Parameters:
+//
+//	ctx - Context of the task
+//	task - Description of the task, e.g. "watchlist poll of xqc mods"
+//	fn - Task to run
+//
+// Returns:
+//
+//	The task's error, or an error wrapping errPanicked if it panicked
+func (b *Bot) guard(ctx context.Context, task string, fn func() error) (err error) {
+	defer func() {
+		p := recover()
+		if p == nil {
+			return
+		}
+
+		report := CrashReport{Kind: "background", Panic: p, Stack: debug.Stack()}
+		if update, ok := ctx.Value(updateContextKey{}).(tgbotapi.Update); ok {
+			report = newCrashReport(update, p, report.Stack)
+		}
+		report.Task = task
+		b.reportPanic(report)
+
+		err = fmt.Errorf("%s: %w: %v", task, errPanicked, p)
+	}()
+
+	return fn()
+}
+
+// reportPanic logs and counts a recovered panic and notifies the crash reporter, if any.
+//
+// Parameters:
+//
+//	report - Report of the panic
+func (b *Bot) reportPanic(report CrashReport) {
+	const op = "bot.reportPanic"
+
+	total := b.panics.Add(1)
+
+	log.Printf("%s: panic #%d in %s (update %d, %s, chat %d, user %d, input %q): %v\n%s",
+		op, total, report.where(), report.UpdateID, report.Kind, report.ChatID, report.UserID, report.Input, report.Panic, report.Stack)
+
+	if b.crashReporter != nil {
+		// A failing reporter must not take the worker down with it.
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("%s: crash reporter panicked: %v", op, p)
+			}
+		}()
+		b.crashReporter(report)
+	}
+}
+
+// Panics returns the number of panics recovered while handling updates and running background tasks.
+//
+// Returns:
+//
+//	The number of recovered panics since the bot was created
+func (b *Bot) Panics() int64 {
+	return b.panics.Load()
+}
+
+// chatCrashReporter creates a crash reporter that posts reports to a Telegram
+// chat through the outgoing message queue without waiting for delivery, so the
+// recovering goroutine is not held up. At most maxCrashReportsPerMinute reports
+// are posted per minute; the others are only logged and counted in the next
+// posted report. Panics after the outgoing queue closed are only logged.
+//
+// Parameters:
+//
+//	chatID - Chat to post the reports to
+//
+// Returns:
+//
+//	A CrashReporter that sends each report as a message
+func (b *Bot) chatCrashReporter(chatID int64) CrashReporter {
+	var (
+		mu         sync.Mutex
+		recent     []time.Time // Times of the reports posted within the last minute
+		suppressed int         // Reports dropped since the last posted one
+	)
+
+	return func(report CrashReport) {
+		const op = "bot.chatCrashReporter"
+
+		now := time.Now()
+
+		mu.Lock()
+		recent = pruneBefore(recent, now.Add(-time.Minute))
+		if len(recent) >= maxCrashReportsPerMinute {
+			suppressed++
+			mu.Unlock()
+			log.Printf("%s: report limit reached, not posting panic in update %d", op, report.UpdateID)
+			return
+		}
+		recent = append(recent, now)
+		dropped := suppressed
+		suppressed = 0
+		mu.Unlock()
+
+		text := fmt.Sprintf("⚠️ Panic in %s (update %d, %s, chat %d, user %d)\nInput: %s\n%s\n<pre>%s</pre>",
+			html.EscapeString(report.where()), report.UpdateID, report.Kind, report.ChatID, report.UserID,
+			html.EscapeString(truncate(report.Input, maxCrashReportInput)),
+			html.EscapeString(truncate(fmt.Sprint(report.Panic), maxCrashReportInput)),
+			html.EscapeString(truncate(string(report.Stack), maxCrashReportStack)))
+		if dropped > 0 {
+			text = fmt.Sprintf("(%d earlier report(s) not posted)\n", dropped) + text
+		}
+
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = tgbotapi.ModeHTML
+		if err := b.sender.post(chatID, msg); err != nil {
+			log.Printf("%s: failed to queue report: %v", op, err)
+		}
+	}
+}
+
+// truncate shortens text to at most limit bytes without splitting a character.
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return strings.ToValidUTF8(text[:limit], "") + "…"
+}
@@ -0,0 +1,204 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/query"
+)
+
+// queryCallbackPrefix is the callback data prefix of sort and filter buttons.
+const queryCallbackPrefix = "q:"
+
+// queryOption is a sort or filter button shown under a list.
+type queryOption struct {
+	label  messageKey
+	lists  []fetcher.Endpoint            // Lists the option applies to
+	active func(query.Query) bool        // Whether the option is part of the query
+	toggle func(query.Query) query.Query // Query after pressing the button
+}
+
+// sortOption creates a button that switches to a sort order, or back to upstream order.
+func sortOption(label messageKey, sort query.Sort, desc bool, lists ...fetcher.Endpoint) queryOption {
+	return queryOption{
+		label: label,
+		lists: lists,
+		active: func(q query.Query) bool {
+			return q.Sort == sort && q.Desc == desc
+		},
+		toggle: func(q query.Query) query.Query {
+			if q.Sort == sort && q.Desc == desc {
+				q.Sort, q.Desc = query.SortNone, false
+			} else {
+				q.Sort, q.Desc = sort, desc
+			}
+			return q
+		},
+	}
+}
+
+// filterOption creates a button that toggles a boolean filter.
+func filterOption(label messageKey, field func(*query.Query) *bool, lists ...fetcher.Endpoint) queryOption {
+	return queryOption{
+		label: label,
+		lists: lists,
+		active: func(q query.Query) bool {
+			return *field(&q)
+		},
+		toggle: func(q query.Query) query.Query {
+			*field(&q) = !*field(&q)
+			return q
+		},
+	}
+}
+
+var allLists = []fetcher.Endpoint{fetcher.EndpointFollows, fetcher.EndpointMods, fetcher.EndpointVips, fetcher.EndpointFounders}
+
+// queryOptions lists the buttons shown under a list, one row per inner slice.
+var queryOptions = [][]queryOption{
+	{
+		sortOption(msgSortOldest, query.SortDate, false, allLists...),
+		sortOption(msgSortNewest, query.SortDate, true, allLists...),
+		sortOption(msgSortName, query.SortName, false, allLists...),
+	},
+	{
+		sortOption(msgSortLive, query.SortLive, false, fetcher.EndpointFollows),
+		filterOption(msgFilterLive, func(q *query.Query) *bool { return &q.Live }, fetcher.EndpointFollows),
+		filterOption(msgFilterBanned, func(q *query.Query) *bool { return &q.Banned },
+			fetcher.EndpointMods, fetcher.EndpointVips, fetcher.EndpointFounders),
+		filterOption(msgFilterSubscribed, func(q *query.Query) *bool { return &q.Subscribed }, fetcher.EndpointFounders),
+	},
+}
+
+// queryKeyboard builds the sort and filter buttons shown under a list.
+// Buttons whose callback data would exceed Telegram's limit are left out.
+//
+// Parameters:
+//
+//	lang - User's language code
+//	username - Twitch username the list was fetched for
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Query the list was rendered with
+//
+// Returns:
+//
+//	The keyboard and false if no button applies to the list
+func queryKeyboard(lang, username, button string, q query.Query) (tgbotapi.InlineKeyboardMarkup, bool) {
+	list, ok := listAliases[button]
+	if !ok || button == "changes" {
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	addButton := func(row []tgbotapi.InlineKeyboardButton, label string, next query.Query) []tgbotapi.InlineKeyboardButton {
+		data := queryCallbackPrefix + listCallbackData(username, button, next)
+		if len(data) > callbackDataLimit {
+			return row
+		}
+		return append(row, tgbotapi.NewInlineKeyboardButtonData(label, data))
+	}
+
+	for _, options := range queryOptions {
+		var row []tgbotapi.InlineKeyboardButton
+		for _, option := range options {
+			if !containsList(option.lists, list) {
+				continue
+			}
+
+			label := localize(lang, option.label)
+			if option.active(q) {
+				label = "✅ " + label
+			}
+			row = addButton(row, label, option.toggle(q))
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	if !q.IsZero() {
+		if row := addButton(nil, localize(lang, msgQueryReset), query.Query{}); len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+// containsList reports whether lists contains list.
+func containsList(lists []fetcher.Endpoint, list fetcher.Endpoint) bool {
+	for _, l := range lists {
+		if l == list {
+			return true
+		}
+	}
+	return false
+}
+
+// listCallbackData encodes a list request as "<button>:<username>[:<query>]".
+//
+// Parameters:
+//
+//	username - Twitch username
+//	button - Selected option (e.g., "follows", "moders")
+//	q - Sorting and filtering of the list
+//
+// Returns:
+//
+//	The callback data without its prefix
+func listCallbackData(username, button string, q query.Query) string {
+	data := button + ":" + username
+	if encoded := q.Encode(); encoded != "" {
+		data += ":" + encoded
+	}
+	return data
+}
+
+// parseListCallback decodes callback data produced by listCallbackData.
+// A malformed query is logged and ignored.
+//
+// Parameters:
+//
+//	data - Callback data without its prefix
+//
+// Returns:
+//
+//	The selected option, the username and the query
+func parseListCallback(data string) (string, string, query.Query) {
+	button, rest, _ := strings.Cut(data, ":")
+	username, encoded, _ := strings.Cut(rest, ":")
+
+	q, err := query.Decode(encoded)
+	if err != nil {
+		log.Printf("bot.parseListCallback: %v", err)
+	}
+
+	return button, username, q
+}
+
+// queryReplyError converts a query flag error into a localized reply.
+//
+// Parameters:
+//
+//	err - Error returned by query.Parse or Query.Validate
+//
+// Returns:
+//
+//	A *replyError describing the offending flag
+func queryReplyError(err error) error {
+	var flagErr *query.FlagError
+	if !errors.As(err, &flagErr) {
+		return err
+	}
+
+	if errors.Is(err, query.ErrUnsupported) {
+		return &replyError{key: msgUnsupportedFlag, args: []any{flagErr.Flag}}
+	}
+	return &replyError{key: msgInvalidFlag, args: []any{flagErr.Flag}}
+}
@@ -0,0 +1,532 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	defaultGlobalPerSecond = 30          // Default messages per second across all chats
+	defaultChatInterval    = time.Second // Default minimum time between messages to one chat
+	defaultGroupPerMinute  = 20          // Default messages per minute to one group chat
+	defaultSendAttempts    = 3           // Default attempts per message, not counting flood waits
+	sendTimeout            = time.Minute // Time limit for delivering a single message
+	sendRetryDelay         = time.Second // Delay before the first retry of a transient failure
+	senderWorkers          = 8           // Number of messages sent at the same time
+)
+
+var (
+	errSenderClosed = errors.New("sender closed before the message was sent")
+	errSendTimeout  = errors.New("message not sent within the time limit")
+)
+
+// SendLimits configures the rate limits of outgoing messages.
+type SendLimits struct {
+	GlobalPerSecond int           // Messages per second across all chats
+	ChatInterval    time.Duration // Minimum time between messages to one chat
+	GroupPerMinute  int           // Messages per minute to one group chat
+	MaxAttempts     int           // Attempts per message, not counting waits requested by Telegram
+}
+
+// defaultSendLimits are the limits recommended by Telegram for bots.
+var defaultSendLimits = SendLimits{
+	GlobalPerSecond: defaultGlobalPerSecond,
+	ChatInterval:    defaultChatInterval,
+	GroupPerMinute:  defaultGroupPerMinute,
+	MaxAttempts:     defaultSendAttempts,
+}
+
+// outgoing is a queued message.
+type outgoing struct {
+	chattable tgbotapi.Chattable
+	attempts  int             // Attempts made so far, not counting flood waits
+	deadline  time.Time       // Time after which the message is given up
+	abandoned bool            // Whether the caller stopped waiting while the message was being sent
+	result    chan sendResult // Receives the delivery result, buffered
+}
+
+// sendResult is the outcome of delivering a message.
+type sendResult struct {
+	msg tgbotapi.Message
+	err error
+}
+
+// chatQueue holds the queued messages and rate limit state of one chat.
+type chatQueue struct {
+	items  []*outgoing
+	busy   bool        // Whether a message of the chat is being sent
+	next   time.Time   // Earliest time the next message may be sent
+	recent []time.Time // Send times within the last minute, group chats only
+}
+
+// messageAPI is the part of the Telegram Bot API used to deliver messages.
+type messageAPI interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+}
+
+// sender queues outgoing messages and delivers them within Telegram's rate
+// limits. Messages to one chat are delivered in order, one at a time.
+type sender struct {
+	api    messageAPI
+	limits SendLimits
+	now    func() time.Time
+
+	mu         sync.Mutex
+	chats      map[int64]*chatQueue
+	globalNext time.Time // Earliest time any message may be sent
+	closed     bool      // No new messages are accepted
+	halted     bool      // No more attempts are made
+
+	wake    chan struct{}  // Signals the scheduler that the queue changed
+	slots   chan struct{}  // Bounds the number of messages being sent
+	pending sync.WaitGroup // Tracks queued and in-flight messages
+	quit    chan struct{}  // Closed to stop the scheduler
+	stopped chan struct{}  // Closed when the scheduler stopped
+}
+
+// newSender creates a sender. Messages are queued until run is called.
+//
+// Parameters:
+//
+//	api - Telegram Bot API instance
+//	limits - Rate limits of outgoing messages
+//
+// Returns:
+//
+//	A pointer to a new sender instance
+func newSender(api messageAPI, limits SendLimits) *sender {
+	return &sender{
+		api:     api,
+		limits:  limits,
+		now:     time.Now,
+		chats:   make(map[int64]*chatQueue),
+		wake:    make(chan struct{}, 1),
+		slots:   make(chan struct{}, senderWorkers),
+		quit:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// send queues a message and waits until it is delivered or given up, or ctx is done.
+// A message still queued when ctx is done is dropped; one being sent is not retried.
+//
+// Parameters:
+//
+//	ctx - Context that aborts waiting for the message
+//	chatID - Chat the message is sent to
+//	c - Message to send
+//
+// Returns:
+//
+//	The sent message and the delivery error, if any
+func (s *sender) send(ctx context.Context, chatID int64, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	item, err := s.enqueue(chatID, c)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	select {
+	case result := <-item.result:
+		return result.msg, result.err
+	case <-ctx.Done():
+		s.abandon(chatID, item)
+		return tgbotapi.Message{}, ctx.Err()
+	}
+}
+
+// post queues a message without waiting for it, e.g. from a goroutine that
+// must not be held up by the rate limits. A failed delivery is logged.
+//
+// Parameters:
+//
+//	chatID - Chat the message is sent to
+//	c - Message to send
+//
+// Returns:
+//
+//	errSenderClosed if the sender no longer accepts messages
+func (s *sender) post(chatID int64, c tgbotapi.Chattable) error {
+	item, err := s.enqueue(chatID, c)
+	if err != nil {
+		return err
+	}
+
+	// Every queued message is finished by its deadline or when the sender closes.
+	go func() {
+		if result := <-item.result; result.err != nil {
+			log.Printf("bot.sender.post: failed to send message to chat %d: %v", chatID, result.err)
+		}
+	}()
+
+	return nil
+}
+
+// enqueue adds a message to its chat's queue and wakes the scheduler.
+//
+// Parameters:
+//
+//	chatID - Chat the message is sent to
+//	c - Message to send
+//
+// Returns:
+//
+//	The queued message and errSenderClosed if the sender no longer accepts messages
+func (s *sender) enqueue(chatID int64, c tgbotapi.Chattable) (*outgoing, error) {
+	item := &outgoing{
+		chattable: c,
+		deadline:  s.now().Add(sendTimeout),
+		result:    make(chan sendResult, 1),
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, errSenderClosed
+	}
+	queue, ok := s.chats[chatID]
+	if !ok {
+		queue = &chatQueue{}
+		s.chats[chatID] = queue
+	}
+	queue.items = append(queue.items, item)
+	s.pending.Add(1)
+	s.mu.Unlock()
+
+	s.signal()
+
+	return item, nil
+}
+
+// abandon drops a message whose caller stopped waiting. A message being sent
+// is marked so it is not retried; its result is discarded.
+//
+// Parameters:
+//
+//	chatID - Chat the message is sent to
+//	item - Abandoned message
+func (s *sender) abandon(chatID int64, item *outgoing) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if queue, ok := s.chats[chatID]; ok {
+		if i := slices.Index(queue.items, item); i >= 0 {
+			queue.items = slices.Delete(queue.items, i, i+1)
+			s.finish(item, sendResult{err: context.Canceled})
+			return
+		}
+	}
+	item.abandoned = true
+}
+
+// run schedules queued messages until the sender is closed.
+func (s *sender) run() {
+	defer close(s.stopped)
+
+	for {
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.quit:
+			return
+		}
+
+		chatID, item, wait := s.reserve(s.now())
+		if item != nil {
+			go s.deliver(chatID, item)
+			continue
+		}
+		<-s.slots
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+
+		select {
+		case <-fire:
+		case <-s.wake:
+		case <-s.quit:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// reserve takes the next message that may be sent now and records the send
+// against the rate limits. Expired messages are given up on the way, including
+// those of chats waiting for a message in flight.
+//
+// Parameters:
+//
+//	now - Current time
+//
+// Returns:
+//
+//	The chat and message to send, or a nil message and the time until one may
+//	be sent or expires, zero if nothing is queued
+func (s *sender) reserve(now time.Time) (int64, *outgoing, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		bestChat  int64
+		bestQueue *chatQueue
+		bestAt    time.Time
+		expiresAt time.Time // Earliest deadline of the messages left queued
+	)
+
+	for chatID, queue := range s.chats {
+		queue.items = slices.DeleteFunc(queue.items, func(item *outgoing) bool {
+			if now.After(item.deadline) {
+				s.finish(item, sendResult{err: errSendTimeout})
+				return true
+			}
+			if expiresAt.IsZero() || item.deadline.Before(expiresAt) {
+				expiresAt = item.deadline
+			}
+			return false
+		})
+
+		if len(queue.items) == 0 {
+			if !queue.busy && s.idle(queue, now) {
+				delete(s.chats, chatID)
+			}
+			continue
+		}
+		if queue.busy {
+			continue
+		}
+
+		at := s.readyAt(chatID, queue)
+		if bestQueue == nil || at.Before(bestAt) {
+			bestChat, bestQueue, bestAt = chatID, queue, at
+		}
+	}
+
+	if bestQueue == nil || bestAt.After(now) {
+		if expiresAt.IsZero() {
+			return 0, nil, 0
+		}
+		// Wake up in time to give up the first message that expires.
+		wait := expiresAt.Sub(now) + time.Millisecond
+		if bestQueue != nil {
+			wait = min(wait, bestAt.Sub(now))
+		}
+		return 0, nil, wait
+	}
+
+	item := bestQueue.items[0]
+	bestQueue.items = bestQueue.items[1:]
+	bestQueue.busy = true
+
+	bestQueue.next = now.Add(s.limits.ChatInterval)
+	if isGroupChat(bestChat) {
+		bestQueue.recent = append(pruneBefore(bestQueue.recent, now.Add(-time.Minute)), now)
+	}
+	s.globalNext = now.Add(time.Second / time.Duration(s.limits.GlobalPerSecond))
+
+	return bestChat, item, 0
+}
+
+// readyAt returns the earliest time the next message of a chat may be sent.
+func (s *sender) readyAt(chatID int64, queue *chatQueue) time.Time {
+	at := queue.next
+	if s.globalNext.After(at) {
+		at = s.globalNext
+	}
+
+	if isGroupChat(chatID) && len(queue.recent) >= s.limits.GroupPerMinute {
+		windowEnd := queue.recent[len(queue.recent)-s.limits.GroupPerMinute].Add(time.Minute)
+		if windowEnd.After(at) {
+			at = windowEnd
+		}
+	}
+
+	return at
+}
+
+// idle reports whether a chat without queued messages no longer limits future sends.
+func (s *sender) idle(queue *chatQueue, now time.Time) bool {
+	if now.Before(queue.next) {
+		return false
+	}
+	return len(queue.recent) == 0 || now.Sub(queue.recent[len(queue.recent)-1]) >= time.Minute
+}
+
+// deliver sends a reserved message and either reports the result or requeues
+// the message at the front of its chat queue for another attempt.
+//
+// Parameters:
+//
+//	chatID - Chat the message is sent to
+//	item - Message to send
+func (s *sender) deliver(chatID int64, item *outgoing) {
+	const op = "bot.sender.deliver"
+
+	defer func() { <-s.slots }()
+
+	msg, err := s.api.Send(item.chattable)
+	delay, retry := s.retryDelay(item, err)
+	now := s.now()
+
+	s.mu.Lock()
+	queue := s.chats[chatID]
+	queue.busy = false
+	if retry && !s.halted && !item.abandoned && now.Add(delay).Before(item.deadline) {
+		log.Printf("%s: retrying message to chat %d in %s: %v", op, chatID, delay, err)
+		queue.items = append([]*outgoing{item}, queue.items...)
+		if next := now.Add(delay); next.After(queue.next) {
+			queue.next = next
+		}
+	} else {
+		s.finish(item, sendResult{msg: msg, err: err})
+	}
+	s.mu.Unlock()
+
+	s.signal()
+}
+
+// retryDelay decides whether a failed send is retried. Flood waits requested
+// by Telegram are always honoured; other transient failures are retried with
+// exponential backoff up to MaxAttempts.
+//
+// Parameters:
+//
+//	item - Message that was sent
+//	err - Send error
+//
+// Returns:
+//
+//	The delay before the next attempt and false if the message is given up
+func (s *sender) retryDelay(item *outgoing, err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == 429 {
+			return time.Duration(max(apiErr.RetryAfter, 1)) * time.Second, true
+		}
+		if apiErr.Code < 500 {
+			// The request itself is invalid, e.g. the bot was blocked by the user.
+			return 0, false
+		}
+	}
+
+	item.attempts++
+	if item.attempts >= s.limits.MaxAttempts {
+		return 0, false
+	}
+	return sendRetryDelay << (item.attempts - 1), true
+}
+
+// finish reports the result of a message. The caller must hold s.mu.
+func (s *sender) finish(item *outgoing, result sendResult) {
+	item.result <- result
+	s.pending.Done()
+}
+
+// signal wakes the scheduler without blocking.
+func (s *sender) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close stops accepting messages and waits for queued and in-flight messages
+// to be delivered. Messages still queued after the timeout are given up.
+//
+// Parameters:
+//
+//	timeout - Time limit for delivering queued messages
+//
+// Returns:
+//
+//	False if some messages were given up
+func (s *sender) close(timeout time.Duration) bool {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+
+	delivered := true
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		delivered = false
+	}
+
+	close(s.quit)
+	<-s.stopped
+
+	s.mu.Lock()
+	s.halted = true
+	for _, queue := range s.chats {
+		for _, item := range queue.items {
+			s.finish(item, sendResult{err: errSenderClosed})
+		}
+		queue.items = nil
+	}
+	s.mu.Unlock()
+
+	return delivered
+}
+
+// send queues a message and waits until it is delivered. Messages are sent
+// within Telegram's rate limits and retried on transient failures.
+//
+// Parameters:
+//
+//	ctx - Context that aborts waiting; replies to an update use its reply context
+//	chatID - Chat the message is sent to
+//	c - Message, document or edit to send
+//
+// Returns:
+//
+//	The sent message and an error if it could not be delivered
+func (b *Bot) send(ctx context.Context, chatID int64, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return b.sender.send(replyContext(ctx), chatID, c)
+}
+
+// sendText sends a plain text message to a chat.
+func (b *Bot) sendText(ctx context.Context, chatID int64, text string) error {
+	if _, err := b.send(ctx, chatID, tgbotapi.NewMessage(chatID, text)); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// isGroupChat reports whether a chat ID belongs to a group, supergroup or channel.
+func isGroupChat(chatID int64) bool {
+	return chatID < 0
+}
+
+// pruneBefore drops the times before cutoff from a sorted slice.
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
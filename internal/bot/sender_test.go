@@ -0,0 +1,410 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// senderStart is the time the controlled clock of a sender test starts at.
+var senderStart = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeAPI records sent messages and fails the calls it is told to.
+type fakeAPI struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	errs     []error  // Results of successive calls; calls past the end succeed
+	attempts []string // Text of every attempted message and the time it was sent
+}
+
+// Send implements messageAPI.
+func (f *fakeAPI) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	text := c.(tgbotapi.MessageConfig).Text
+	f.attempts = append(f.attempts, text+"@"+f.now().Sub(senderStart).String())
+
+	var err error
+	if n := len(f.attempts) - 1; n < len(f.errs) {
+		err = f.errs[n]
+	}
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+	return tgbotapi.Message{Text: text}, nil
+}
+
+// sent returns the recorded attempts.
+func (f *fakeAPI) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.attempts)
+}
+
+// newTestSender creates a sender with a fake API and a clock the test controls.
+func newTestSender(limits SendLimits, errs ...error) (*sender, *fakeAPI, *time.Time) {
+	clock := senderStart
+	now := func() time.Time { return clock }
+
+	api := &fakeAPI{now: now, errs: errs}
+	s := newSender(api, limits)
+	s.now = now
+	return s, api, &clock
+}
+
+// step reserves the message that may be sent at the sender's current time and
+// delivers it synchronously, as the scheduler would on its own goroutine.
+//
+// Returns:
+//
+//	Whether a message was sent and otherwise the time until one may be sent
+func step(s *sender) (bool, time.Duration) {
+	s.slots <- struct{}{}
+	chatID, item, wait := s.reserve(s.now())
+	if item == nil {
+		<-s.slots
+		return false, wait
+	}
+	s.deliver(chatID, item)
+	return true, 0
+}
+
+// runClock steps the sender, advancing the clock to the next send, until
+// nothing is queued.
+func runClock(t *testing.T, s *sender, clock *time.Time) {
+	t.Helper()
+
+	for range 1000 {
+		sent, wait := step(s)
+		if sent {
+			continue
+		}
+		if wait == 0 {
+			return
+		}
+		*clock = clock.Add(wait)
+	}
+	t.Fatal("messages still queued after 1000 steps")
+}
+
+// enqueue queues a text message and returns its result channel.
+func enqueue(t *testing.T, s *sender, chatID int64, text string) *outgoing {
+	t.Helper()
+
+	item, err := s.enqueue(chatID, tgbotapi.NewMessage(chatID, text))
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	return item
+}
+
+func TestSenderLimits(t *testing.T) {
+	type message struct {
+		chatID int64
+		text   string
+	}
+
+	tests := []struct {
+		name     string
+		limits   SendLimits
+		messages []message
+		want     []string // Attempts as "text@offset", in send order
+	}{
+		{
+			name:     "one message per chat interval",
+			limits:   SendLimits{GlobalPerSecond: 30, ChatInterval: time.Second, GroupPerMinute: 20, MaxAttempts: 3},
+			messages: []message{{1, "a"}, {1, "b"}, {1, "c"}},
+			want:     []string{"a@0s", "b@1s", "c@2s"},
+		},
+		{
+			name:     "global limit spaces every send",
+			limits:   SendLimits{GlobalPerSecond: 2, GroupPerMinute: 20, MaxAttempts: 3},
+			messages: []message{{1, "a"}, {1, "b"}, {1, "c"}},
+			want:     []string{"a@0s", "b@500ms", "c@1s"},
+		},
+		{
+			name:     "global limit outlasts a shorter chat interval",
+			limits:   SendLimits{GlobalPerSecond: 1, ChatInterval: 100 * time.Millisecond, GroupPerMinute: 20, MaxAttempts: 3},
+			messages: []message{{1, "a"}, {1, "b"}},
+			want:     []string{"a@0s", "b@1s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, api, clock := newTestSender(tt.limits)
+
+			var items []*outgoing
+			for _, m := range tt.messages {
+				items = append(items, enqueue(t, s, m.chatID, m.text))
+			}
+
+			runClock(t, s, clock)
+
+			if got := api.sent(); !slices.Equal(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+			for i, item := range items {
+				if result := <-item.result; result.err != nil {
+					t.Errorf("message %d: %v", i, result.err)
+				}
+			}
+		})
+	}
+}
+
+func TestSenderGroupLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		chatID int64
+		want   []string
+	}{
+		{name: "group chats are limited per minute", chatID: -100, want: []string{"a@0s", "b@1s", "c@1m0s"}},
+		{name: "private chats are not", chatID: 5, want: []string{"a@0s", "b@1s", "c@30s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, api, clock := newTestSender(SendLimits{GlobalPerSecond: 30, ChatInterval: time.Second, GroupPerMinute: 2, MaxAttempts: 3})
+
+			enqueue(t, s, tt.chatID, "a")
+			enqueue(t, s, tt.chatID, "b")
+			runClock(t, s, clock)
+
+			// Queued later, so the message does not expire before the minute is over.
+			*clock = senderStart.Add(30 * time.Second)
+			enqueue(t, s, tt.chatID, "c")
+			runClock(t, s, clock)
+
+			if got := api.sent(); !slices.Equal(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSenderRetries(t *testing.T) {
+	floodWait := func(seconds int) error {
+		return &tgbotapi.Error{Code: 429, Message: "Too Many Requests", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: seconds}}
+	}
+	serverError := &tgbotapi.Error{Code: 502, Message: "Bad Gateway"}
+	forbidden := &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		errs        []error
+		want        []string // Attempts as "text@offset"
+		wantErr     error
+	}{
+		{
+			name:        "flood wait is honoured",
+			maxAttempts: 3,
+			errs:        []error{floodWait(5)},
+			want:        []string{"m@0s", "m@5s"},
+		},
+		{
+			name:        "flood waits do not count as attempts",
+			maxAttempts: 1,
+			errs:        []error{floodWait(2), floodWait(2)},
+			want:        []string{"m@0s", "m@2s", "m@4s"},
+		},
+		{
+			name:        "flood wait past the deadline gives up",
+			maxAttempts: 3,
+			errs:        []error{floodWait(int(sendTimeout / time.Second))},
+			want:        []string{"m@0s"},
+			wantErr:     floodWait(60),
+		},
+		{
+			name:        "server errors back off exponentially",
+			maxAttempts: 3,
+			errs:        []error{serverError, serverError},
+			want:        []string{"m@0s", "m@1s", "m@3s"},
+		},
+		{
+			name:        "server errors stop after max attempts",
+			maxAttempts: 3,
+			errs:        []error{serverError, serverError, serverError},
+			want:        []string{"m@0s", "m@1s", "m@3s"},
+			wantErr:     serverError,
+		},
+		{
+			name:        "network errors are retried",
+			maxAttempts: 2,
+			errs:        []error{errors.New("connection reset")},
+			want:        []string{"m@0s", "m@1s"},
+		},
+		{
+			name:        "client errors are not retried",
+			maxAttempts: 3,
+			errs:        []error{forbidden},
+			want:        []string{"m@0s"},
+			wantErr:     forbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := defaultSendLimits
+			limits.MaxAttempts = tt.maxAttempts
+			s, api, clock := newTestSender(limits, tt.errs...)
+
+			item := enqueue(t, s, 1, "m")
+			runClock(t, s, clock)
+
+			if got := api.sent(); !slices.Equal(got, tt.want) {
+				t.Errorf("attempts %v, want %v", got, tt.want)
+			}
+
+			result := <-item.result
+			if (result.err == nil) != (tt.wantErr == nil) || (result.err != nil && result.err.Error() != tt.wantErr.Error()) {
+				t.Errorf("error %v, want %v", result.err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSenderAbandon(t *testing.T) {
+	t.Run("queued message is dropped", func(t *testing.T) {
+		s, api, clock := newTestSender(defaultSendLimits)
+
+		first := enqueue(t, s, 1, "a")
+		second := enqueue(t, s, 1, "b")
+		step(s)
+
+		s.abandon(1, second)
+		if result := <-second.result; !errors.Is(result.err, context.Canceled) {
+			t.Errorf("abandoned message: %v, want context.Canceled", result.err)
+		}
+
+		runClock(t, s, clock)
+		if got := api.sent(); !slices.Equal(got, []string{"a@0s"}) {
+			t.Errorf("sent %v, want only the first message", got)
+		}
+		if result := <-first.result; result.err != nil {
+			t.Errorf("first message: %v", result.err)
+		}
+	})
+
+	t.Run("message in flight is not retried", func(t *testing.T) {
+		s, api, clock := newTestSender(defaultSendLimits, &tgbotapi.Error{Code: 500, Message: "Internal Server Error"})
+
+		item := enqueue(t, s, 1, "a")
+		s.slots <- struct{}{}
+		chatID, reserved, _ := s.reserve(s.now())
+		s.abandon(chatID, reserved)
+		s.deliver(chatID, reserved)
+
+		runClock(t, s, clock)
+		if got := api.sent(); len(got) != 1 {
+			t.Errorf("attempts %v, want one", got)
+		}
+		if result := <-item.result; result.err == nil {
+			t.Error("abandoned message reported as sent")
+		}
+	})
+
+	t.Run("send returns when the context is done", func(t *testing.T) {
+		s, api, _ := newTestSender(defaultSendLimits)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// The scheduler is not running, so the message can only be dropped.
+		if _, err := s.send(ctx, 1, tgbotapi.NewMessage(1, "a")); !errors.Is(err, context.Canceled) {
+			t.Errorf("send: %v, want context.Canceled", err)
+		}
+		if len(s.chats[1].items) != 0 || len(api.sent()) != 0 {
+			t.Error("cancelled message is still queued")
+		}
+	})
+}
+
+func TestSenderExpiresQueuedMessagesOfBusyChat(t *testing.T) {
+	s, _, clock := newTestSender(defaultSendLimits, &tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 50}})
+
+	stuck := enqueue(t, s, 1, "a")
+	waiting := enqueue(t, s, 1, "b")
+
+	// "a" is in flight while "b" outlives its deadline.
+	s.slots <- struct{}{}
+	chatID, item, _ := s.reserve(s.now())
+	*clock = clock.Add(sendTimeout + time.Second)
+	if _, next, _ := s.reserve(s.now()); next != nil {
+		t.Fatal("message reserved while its chat is busy")
+	}
+
+	select {
+	case result := <-waiting.result:
+		if !errors.Is(result.err, errSendTimeout) {
+			t.Errorf("expired message: %v, want errSendTimeout", result.err)
+		}
+	default:
+		t.Fatal("expired message of a busy chat is still queued")
+	}
+
+	s.deliver(chatID, item)
+	if result := <-stuck.result; result.err == nil {
+		t.Error("message past its deadline was retried")
+	}
+}
+
+func TestSenderClose(t *testing.T) {
+	t.Run("delivers queued messages", func(t *testing.T) {
+		api := &fakeAPI{now: time.Now}
+		s := newSender(api, SendLimits{GlobalPerSecond: 1000, ChatInterval: time.Millisecond, GroupPerMinute: 20, MaxAttempts: 3})
+		go s.run()
+
+		var items []*outgoing
+		for _, text := range []string{"a", "b", "c"} {
+			items = append(items, enqueue(t, s, 1, text))
+		}
+		if err := s.post(2, tgbotapi.NewMessage(2, "d")); err != nil {
+			t.Fatalf("post: %v", err)
+		}
+
+		if !s.close(time.Second) {
+			t.Error("close gave up on messages")
+		}
+		for i, item := range items {
+			if result := <-item.result; result.err != nil {
+				t.Errorf("message %d: %v", i, result.err)
+			}
+		}
+		if got := api.sent(); len(got) != 4 {
+			t.Errorf("sent %v, want 4 messages", got)
+		}
+		if _, err := s.send(context.Background(), 1, tgbotapi.NewMessage(1, "late")); !errors.Is(err, errSenderClosed) {
+			t.Errorf("send after close: %v, want errSenderClosed", err)
+		}
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		api := &fakeAPI{now: time.Now, errs: []error{&tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 30}}}}
+		s := newSender(api, defaultSendLimits)
+		go s.run()
+
+		item := enqueue(t, s, 1, "a")
+		for len(api.sent()) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		if s.close(50 * time.Millisecond) {
+			t.Error("close reported a message stuck in a flood wait as delivered")
+		}
+		if result := <-item.result; !errors.Is(result.err, errSenderClosed) {
+			t.Errorf("stuck message: %v, want errSenderClosed", result.err)
+		}
+		if got := api.sent(); len(got) != 1 || !strings.HasPrefix(got[0], "a@") {
+			t.Errorf("attempts %v, want one", got)
+		}
+	})
+}
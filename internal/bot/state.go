@@ -0,0 +1,201 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// defaultStateTTL is how long an unanswered prompt is remembered by default.
+const defaultStateTTL = 10 * time.Minute
+
+// StateKey identifies a conversation with a single user within a chat, so
+// prompts in group chats are answered by the user who triggered them.
+type StateKey struct {
+	ChatID int64
+	UserID int64
+}
+
+// StateStore defines an interface for storing per-conversation state.
+// Implementations must be safe for concurrent use and forget states after their TTL.
+type StateStore interface {
+	Get(key StateKey) (UserState, bool, error)
+	Set(key StateKey, state UserState) error
+	Delete(key StateKey) error
+}
+
+// StateBackend defines an interface for persisting encoded conversation states.
+type StateBackend interface {
+	LoadState(chatID, userID int64) ([]byte, bool, error)
+	SaveState(chatID, userID int64, data []byte, expiresAt time.Time) error
+	DeleteState(chatID, userID int64) error
+}
+
+// memoryState is a stored state with its expiry time.
+type memoryState struct {
+	state     UserState
+	expiresAt time.Time
+}
+
+// MemoryStateStore keeps conversation states in memory.
+type MemoryStateStore struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	states    map[StateKey]memoryState
+	lastSweep time.Time
+}
+
+// NewMemoryStateStore creates an in-memory StateStore.
+//
+// Parameters:
+//
+//	ttl - Time after which an unanswered state is forgotten
+//
+// Returns:
+//
+//	A pointer to a new MemoryStateStore instance
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	return &MemoryStateStore{
+		ttl:       ttl,
+		states:    make(map[StateKey]memoryState),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the unexpired state of a conversation.
+func (s *MemoryStateStore) Get(key StateKey) (UserState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.states[key]
+	if !ok {
+		return UserState{}, false, nil
+	}
+
+	if time.Now().After(stored.expiresAt) {
+		delete(s.states, key)
+		return UserState{}, false, nil
+	}
+
+	return stored.state, true, nil
+}
+
+// Set stores the state of a conversation and periodically sweeps expired states.
+func (s *MemoryStateStore) Set(key StateKey, state UserState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.states[key] = memoryState{state: state, expiresAt: now.Add(s.ttl)}
+
+	if now.Sub(s.lastSweep) > s.ttl {
+		for k, stored := range s.states {
+			if now.After(stored.expiresAt) {
+				delete(s.states, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	return nil
+}
+
+// Delete forgets the state of a conversation.
+func (s *MemoryStateStore) Delete(key StateKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+	return nil
+}
+
+// PersistentStateStore keeps conversation states in a StateBackend so pending
+// prompts survive restarts.
+type PersistentStateStore struct {
+	backend StateBackend
+	ttl     time.Duration
+}
+
+// NewPersistentStateStore creates a StateStore backed by persistent storage.
+//
+// Parameters:
+//
+//	backend - Storage for encoded states
+//	ttl - Time after which an unanswered state is forgotten
+//
+// Returns:
+//
+//	A pointer to a new PersistentStateStore instance
+func NewPersistentStateStore(backend StateBackend, ttl time.Duration) *PersistentStateStore {
+	return &PersistentStateStore{
+		backend: backend,
+		ttl:     ttl,
+	}
+}
+
+// Get returns the unexpired state of a conversation.
+func (s *PersistentStateStore) Get(key StateKey) (UserState, bool, error) {
+	data, ok, err := s.backend.LoadState(key.ChatID, key.UserID)
+	if err != nil || !ok {
+		return UserState{}, false, err
+	}
+
+	var state UserState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return UserState{}, false, fmt.Errorf("failed to decode state: %w", err)
+	}
+
+	return state, true, nil
+}
+
+// Set stores the state of a conversation.
+func (s *PersistentStateStore) Set(key StateKey, state UserState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	return s.backend.SaveState(key.ChatID, key.UserID, data, time.Now().Add(s.ttl))
+}
+
+// Delete forgets the state of a conversation.
+func (s *PersistentStateStore) Delete(key StateKey) error {
+	return s.backend.DeleteState(key.ChatID, key.UserID)
+}
+
+// messageStateKey returns the conversation key of a message.
+//
+// Parameters:
+//
+//	msg - Telegram message
+//
+// Returns:
+//
+//	The key of the sender's conversation in the message's chat
+func messageStateKey(msg *tgbotapi.Message) StateKey {
+	key := StateKey{ChatID: msg.Chat.ID}
+	if msg.From != nil {
+		key.UserID = msg.From.ID
+	}
+	return key
+}
+
+// callbackStateKey returns the conversation key of a callback query.
+//
+// Parameters:
+//
+//	callback - Telegram callback query
+//
+// Returns:
+//
+//	The key of the presser's conversation in the callback message's chat
+func callbackStateKey(callback *tgbotapi.CallbackQuery) StateKey {
+	key := StateKey{UserID: callback.From.ID}
+	if callback.Message != nil {
+		key.ChatID = callback.Message.Chat.ID
+	}
+	return key
+}
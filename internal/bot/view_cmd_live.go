@@ -0,0 +1,236 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
+)
+
+// ViewCmdGoLive creates a view handler for the /golive command.
+// "/golive <login>" registers the chat for notifications about channels the
+// login follows going live, "/golive off" removes the registration and
+// "/golive" shows the current settings.
+//
+// Returns:
+//
+//	A ViewFunc that handles the golive command interaction
+func (b *Bot) ViewCmdGoLive() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+		fields := strings.Fields(update.Message.CommandArguments())
+
+		switch {
+		case len(fields) == 0:
+			sub, ok, err := b.live.subs.LiveSubscription(chatID)
+			if err != nil {
+				return fmt.Errorf("failed to load live subscription: %w", err)
+			}
+			if !ok {
+				return b.sendText(ctx, chatID, localize(lang, msgGoLiveUsage))
+			}
+			return b.sendText(ctx, chatID, describeLiveSubscription(lang, sub))
+
+		case len(fields) == 1 && strings.EqualFold(fields[0], "off"):
+			removed, err := b.live.subs.DeleteLiveSubscription(chatID)
+			if err != nil {
+				return fmt.Errorf("failed to remove live subscription: %w", err)
+			}
+			if !removed {
+				return b.sendText(ctx, chatID, localize(lang, msgGoLiveNotRegistered))
+			}
+			return b.sendText(ctx, chatID, localize(lang, msgGoLiveDisabled))
+
+		case len(fields) == 1:
+			login, err := parseChannel(fields[0])
+			if err != nil {
+				return b.sendText(ctx, chatID, replyText(lang, err))
+			}
+
+			sub, _, err := b.live.subs.LiveSubscription(chatID)
+			if err != nil {
+				return fmt.Errorf("failed to load live subscription: %w", err)
+			}
+
+			sub.ChatID = chatID
+			sub.Login = login
+			if sub.CreatedAt.IsZero() {
+				sub.CreatedAt = time.Now()
+			}
+
+			if err := b.live.subs.SaveLiveSubscription(sub); err != nil {
+				return fmt.Errorf("failed to save live subscription: %w", err)
+			}
+			return b.sendText(ctx, chatID, localize(lang, msgGoLiveEnabled, sub.Login))
+		}
+
+		return b.sendText(ctx, chatID, localize(lang, msgGoLiveUsage))
+	}
+}
+
+// ViewCmdMute creates a view handler for the /mute command.
+// It stops go-live notifications for a single channel.
+//
+// Returns:
+//
+//	A ViewFunc that handles the mute command interaction
+func (b *Bot) ViewCmdMute() ViewFunc {
+	return b.updateLiveSubscription(func(lang string, sub *storage.LiveSubscription, args []string) (string, bool) {
+		if len(args) != 1 {
+			return localize(lang, msgMuteUsage), false
+		}
+
+		channel, err := parseChannel(args[0])
+		if err != nil {
+			return replyText(lang, err), false
+		}
+		if !slices.Contains(sub.Muted, channel) {
+			sub.Muted = append(sub.Muted, channel)
+		}
+		return localize(lang, msgMuted, channel), true
+	})
+}
+
+// ViewCmdUnmute creates a view handler for the /unmute command.
+// It resumes go-live notifications for a muted channel.
+//
+// Returns:
+//
+//	A ViewFunc that handles the unmute command interaction
+func (b *Bot) ViewCmdUnmute() ViewFunc {
+	return b.updateLiveSubscription(func(lang string, sub *storage.LiveSubscription, args []string) (string, bool) {
+		if len(args) != 1 {
+			return localize(lang, msgUnmuteUsage), false
+		}
+
+		channel, err := parseChannel(args[0])
+		if err != nil {
+			return replyText(lang, err), false
+		}
+		sub.Muted = slices.DeleteFunc(sub.Muted, func(muted string) bool { return muted == channel })
+		return localize(lang, msgUnmuted, channel), true
+	})
+}
+
+// ViewCmdQuiet creates a view handler for the /quiet command.
+// "/quiet 23-8 +3" suppresses go-live notifications from 23:00 to 08:00 at
+// UTC+3 and "/quiet off" disables quiet hours.
+//
+// Returns:
+//
+//	A ViewFunc that handles the quiet command interaction
+func (b *Bot) ViewCmdQuiet() ViewFunc {
+	return b.updateLiveSubscription(func(lang string, sub *storage.LiveSubscription, args []string) (string, bool) {
+		if len(args) == 1 && strings.EqualFold(args[0], "off") {
+			sub.QuietFrom, sub.QuietTo = 0, 0
+			return localize(lang, msgQuietDisabled), true
+		}
+
+		from, to, offset, ok := parseQuietHours(args)
+		if !ok {
+			return localize(lang, msgQuietUsage), false
+		}
+
+		sub.QuietFrom, sub.QuietTo, sub.UTCOffset = from, to, offset
+		return localize(lang, msgQuietEnabled, from, to, formatUTCOffset(offset)), true
+	})
+}
+
+// updateLiveSubscription creates a view handler that modifies the chat's go-live registration.
+//
+// Parameters:
+//
+//	update - Applies the command arguments to the registration and returns the
+//	         reply and whether the registration should be saved
+//
+// Returns:
+//
+//	A ViewFunc that loads, updates and saves the registration
+func (b *Bot) updateLiveSubscription(update func(lang string, sub *storage.LiveSubscription, args []string) (string, bool)) ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, upd tgbotapi.Update) error {
+		lang := messageLang(upd.Message)
+		chatID := upd.Message.Chat.ID
+
+		sub, ok, err := b.live.subs.LiveSubscription(chatID)
+		if err != nil {
+			return fmt.Errorf("failed to load live subscription: %w", err)
+		}
+		if !ok {
+			return b.sendText(ctx, chatID, localize(lang, msgGoLiveNotRegistered))
+		}
+
+		reply, save := update(lang, &sub, strings.Fields(upd.Message.CommandArguments()))
+		if save {
+			if err := b.live.subs.SaveLiveSubscription(sub); err != nil {
+				return fmt.Errorf("failed to save live subscription: %w", err)
+			}
+		}
+
+		return b.sendText(ctx, chatID, reply)
+	}
+}
+
+// parseQuietHours parses "<from>-<to> [UTC offset]", e.g. "23-8 +3".
+//
+// Parameters:
+//
+//	args - Command arguments
+//
+// Returns:
+//
+//	The start hour, end hour, UTC offset and whether the arguments are valid
+func parseQuietHours(args []string) (int, int, int, bool) {
+	if len(args) == 0 || len(args) > 2 {
+		return 0, 0, 0, false
+	}
+
+	fromText, toText, ok := strings.Cut(args[0], "-")
+	if !ok {
+		return 0, 0, 0, false
+	}
+
+	from, err := strconv.Atoi(fromText)
+	if err != nil || from < 0 || from > 23 {
+		return 0, 0, 0, false
+	}
+
+	to, err := strconv.Atoi(toText)
+	if err != nil || to < 0 || to > 23 || to == from {
+		return 0, 0, 0, false
+	}
+
+	offset := 0
+	if len(args) == 2 {
+		offset, err = strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(args[1]), "UTC"), "+"))
+		if err != nil || offset < -12 || offset > 14 {
+			return 0, 0, 0, false
+		}
+	}
+
+	return from, to, offset, true
+}
+
+// describeLiveSubscription renders a chat's go-live settings.
+func describeLiveSubscription(lang string, sub storage.LiveSubscription) string {
+	text := localize(lang, msgGoLiveStatus, sub.Login)
+
+	if sub.QuietFrom != sub.QuietTo {
+		text += "\n" + localize(lang, msgQuietEnabled, sub.QuietFrom, sub.QuietTo, formatUTCOffset(sub.UTCOffset))
+	}
+	if len(sub.Muted) > 0 {
+		text += "\n" + localize(lang, msgMutedList, strings.Join(sub.Muted, ", "))
+	}
+
+	return text
+}
+
+// formatUTCOffset renders a UTC offset in hours, e.g. "UTC+3".
+func formatUTCOffset(offset int) string {
+	return fmt.Sprintf("UTC%+d", offset)
+}
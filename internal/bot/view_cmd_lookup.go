@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ViewCmdLookup creates a view handler for the list commands, e.g. "/mods xqc",
+// "/mods xqc, shroud" or "/mods xqc --sort=-date --banned". Without an argument
+// it starts the same prompt as the start keyboard button.
+//
+// Parameters:
+//
+//	button - List to fetch: "follows", "moders", "vips" or "founders"
+//
+// Returns:
+//
+//	A ViewFunc that handles the list command interaction
+func (b *Bot) ViewCmdLookup(button string) ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		args := update.Message.CommandArguments()
+		if strings.TrimSpace(args) == "" {
+			conv := Conversation{Key: messageStateKey(update.Message), Lang: lang}
+			b.startFlow(ctx, update, conv, flowLookup, map[string]string{"list": button})
+			return nil
+		}
+
+		if err := b.lookup(ctx, chatID, lang, args, button); err != nil {
+			return b.sendText(ctx, chatID, replyText(lang, err))
+		}
+		return nil
+	}
+}
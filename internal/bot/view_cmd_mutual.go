@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/analytics"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/formatter"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+// ViewCmdMutual creates a view handler for the /mutual command.
+// It compares the follow lists of two users, e.g. "/mutual xqc shroud".
+//
+// Returns:
+//
+//	A ViewFunc that handles the mutual command interaction
+func (b *Bot) ViewCmdMutual() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		const op = "bot.ViewCmdMutual"
+
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		inputs := utils.SplitUsernames(update.Message.CommandArguments())
+		if len(inputs) != 2 {
+			return b.sendText(ctx, chatID, localize(lang, msgMutualUsage))
+		}
+
+		var (
+			logins   [2]string
+			follows  [2][]fetcher.Follow
+			failures []string
+		)
+		for i, input := range inputs {
+			login, err := parseChannel(input)
+			if err != nil {
+				return b.sendText(ctx, chatID, replyText(lang, err))
+			}
+			logins[i] = login
+		}
+
+		errs := b.fanOut(ctx, len(logins), len(logins), func(ctx context.Context, i int) error {
+			var err error
+			follows[i], err = b.fetcher.FetchFollows(ctx, logins[i])
+			// Following nobody is a valid answer when comparing users.
+			if errors.Is(err, fetcher.ErrEmptyList) {
+				return nil
+			}
+			return err
+		})
+
+		for i, err := range errs {
+			if err != nil {
+				log.Printf("%s: %s: %v", op, logins[i], err)
+				text, _ := describeFetchError(lang, logins[i], "follows", err)
+				failures = append(failures, formatFailure(logins[i], text))
+			}
+		}
+
+		response := strings.Join(failures, "\n")
+		if len(failures) == 0 {
+			comparison := analytics.CompareFollows(logins[0], follows[0], logins[1], follows[1])
+			response = formatter.FormatFollowComparison(comparison)
+		}
+
+		b.sendPaged(ctx, chatID, 0, response, nil)
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/analytics"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/formatter"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+// minOverlapChannels is the number of channels needed to compute an overlap.
+const minOverlapChannels = 2
+
+// ViewCmdOverlap creates a view handler for the /overlap command.
+// It reports users who are mods or VIPs in more than one of the given channels,
+// e.g. "/overlap xqc, shroud, summit1g" or "/overlap xqc shroud mods" to compare
+// only moderators.
+//
+// Returns:
+//
+//	A ViewFunc that handles the overlap command interaction
+func (b *Bot) ViewCmdOverlap() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		const op = "bot.ViewCmdOverlap"
+
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		inputs, lists := parseOverlapArgs(update.Message.CommandArguments())
+		if len(inputs) < minOverlapChannels {
+			return b.sendText(ctx, chatID, localize(lang, msgOverlapUsage))
+		}
+		if len(inputs) > b.batchSize {
+			return b.sendText(ctx, chatID, localize(lang, msgBatchTooLarge, b.batchSize))
+		}
+
+		var (
+			channels []string
+			failures []string
+		)
+		for _, input := range inputs {
+			channel, err := parseChannel(input)
+			if err != nil {
+				failures = append(failures, formatFailure(input, replyText(lang, err)))
+				continue
+			}
+			channels = append(channels, channel)
+		}
+
+		roles := make([]analytics.ChannelRoles, len(channels))
+		errs := b.fanOut(ctx, len(channels), b.batchConcurrency, func(ctx context.Context, i int) error {
+			var err error
+			roles[i], err = b.fetchRoles(ctx, channels[i], lists)
+			return err
+		})
+
+		var fetched []analytics.ChannelRoles
+		for i, err := range errs {
+			if err != nil {
+				log.Printf("%s: %s: %v", op, channels[i], err)
+				text, _ := describeFetchError(lang, channels[i], "moders", err)
+				failures = append(failures, formatFailure(channels[i], text))
+				continue
+			}
+			fetched = append(fetched, roles[i])
+		}
+
+		var response string
+		if len(failures) > 0 {
+			response = strings.Join(failures, "\n") + "\n"
+		}
+		if len(fetched) < minOverlapChannels {
+			response += localize(lang, msgOverlapTooFew)
+		} else {
+			overlap := analytics.Overlaps(fetched)
+
+			// Pagination splits on lines and could cut the matrix's <pre> block into
+			// invalid HTML, so the matrix is sent separately in self-contained parts.
+			for _, part := range formatter.FormatOverlapMatrix(overlap, telegramMessageLimit) {
+				msg := tgbotapi.NewMessage(chatID, part)
+				msg.ParseMode = tgbotapi.ModeHTML
+				if _, err := b.send(ctx, chatID, msg); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+			}
+
+			response += formatter.FormatOverlap(overlap)
+		}
+
+		b.sendPaged(ctx, chatID, 0, response, nil)
+		return nil
+	}
+}
+
+// fetchRoles fetches the requested privileged-user lists of a channel.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	channel - Twitch channel name
+//	lists - Lists to fetch: EndpointMods, EndpointVips or both
+//
+// Returns:
+//
+//	The channel's roles and an error if a list could not be fetched
+func (b *Bot) fetchRoles(ctx context.Context, channel string, lists []fetcher.Endpoint) (analytics.ChannelRoles, error) {
+	roles := analytics.ChannelRoles{Channel: channel}
+
+	for _, list := range lists {
+		var err error
+		switch list {
+		case fetcher.EndpointMods:
+			roles.Mods, err = b.fetcher.FetchMods(ctx, channel)
+		case fetcher.EndpointVips:
+			roles.Vips, err = b.fetcher.FetchVips(ctx, channel)
+		}
+
+		// An empty list is a valid answer when comparing channels.
+		if err != nil && !errors.Is(err, fetcher.ErrEmptyList) {
+			return analytics.ChannelRoles{}, err
+		}
+	}
+
+	return roles, nil
+}
+
+// parseOverlapArgs splits overlap arguments into channels and list filters.
+//
+// Parameters:
+//
+//	args - Command arguments
+//
+// Returns:
+//
+//	The channels as typed and the lists to compare, mods and VIPs by default
+func parseOverlapArgs(args string) ([]string, []fetcher.Endpoint) {
+	var (
+		channels []string
+		lists    []fetcher.Endpoint
+	)
+
+	for _, field := range utils.SplitUsernames(args) {
+		list, ok := listAliases[strings.ToLower(field)]
+		if ok && (list == fetcher.EndpointMods || list == fetcher.EndpointVips) {
+			if !slices.Contains(lists, list) {
+				lists = append(lists, list)
+			}
+			continue
+		}
+		channels = append(channels, field)
+	}
+
+	if len(lists) == 0 {
+		lists = []fetcher.Endpoint{fetcher.EndpointMods, fetcher.EndpointVips}
+	}
+
+	return channels, lists
+}
+
+// formatFailure renders a per-channel failure line of a multi-channel report.
+func formatFailure(channel, reason string) string {
+	return fmt.Sprintf("⚠️ <b>%s</b>: %s", html.EscapeString(channel), html.EscapeString(reason))
+}
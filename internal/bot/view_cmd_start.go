@@ -13,7 +13,7 @@ import (
 // Returns:
 //
 //	A ViewFunc that handles the start command interaction
-func ViewCmdStart() ViewFunc {
+func (b *Bot) ViewCmdStart() ViewFunc {
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
@@ -24,12 +24,15 @@ func ViewCmdStart() ViewFunc {
 				tgbotapi.NewInlineKeyboardButtonData("vips", "vips"),
 				tgbotapi.NewInlineKeyboardButtonData("founders", "founders"),
 			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("changes", "changes"),
+			),
 		)
 
 		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Select the option:")
 		msg.ReplyMarkup = inlineKeyboard
 
-		_, err := bot.Send(msg)
+		_, err := b.send(ctx, update.Message.Chat.ID, msg)
 		if err != nil {
 			return fmt.Errorf("failed to send message: %w", err)
 		}
@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
+)
+
+// ViewCmdWatch creates a view handler for the /watch command.
+// It subscribes the chat to changes of a channel's mod, VIP and founder lists,
+// e.g. "/watch xqc mods vips"; without list names all three are watched.
+//
+// Returns:
+//
+//	A ViewFunc that handles the watch command interaction
+func (b *Bot) ViewCmdWatch() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		input, lists, ok := parseWatchArgs(update.Message.CommandArguments())
+		if !ok {
+			return b.sendText(ctx, chatID, localize(lang, msgWatchUsage))
+		}
+
+		channel, err := parseChannel(input)
+		if err != nil {
+			return b.sendText(ctx, chatID, replyText(lang, err))
+		}
+
+		subs, err := b.watcher.subs.Subscriptions(chatID)
+		if err != nil {
+			return fmt.Errorf("failed to load subscriptions: %w", err)
+		}
+
+		if b.watcher.maxSubs > 0 && len(subs) >= b.watcher.maxSubs && !isSubscribed(subs, channel) {
+			return b.sendText(ctx, chatID, localize(lang, msgWatchLimit, b.watcher.maxSubs))
+		}
+
+		err = b.watcher.subs.Subscribe(storage.Subscription{
+			ChatID:    chatID,
+			Channel:   channel,
+			Lists:     lists,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save subscription: %w", err)
+		}
+
+		return b.sendText(ctx, chatID, localize(lang, msgWatchAdded, channel, joinLists(lists)))
+	}
+}
+
+// ViewCmdUnwatch creates a view handler for the /unwatch command.
+// It removes the chat's subscription to a channel.
+//
+// Returns:
+//
+//	A ViewFunc that handles the unwatch command interaction
+func (b *Bot) ViewCmdUnwatch() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		fields := strings.Fields(update.Message.CommandArguments())
+		if len(fields) != 1 {
+			return b.sendText(ctx, chatID, localize(lang, msgUnwatchUsage))
+		}
+		channel, err := parseChannel(fields[0])
+		if err != nil {
+			return b.sendText(ctx, chatID, replyText(lang, err))
+		}
+
+		removed, err := b.watcher.subs.Unsubscribe(chatID, channel)
+		if err != nil {
+			return fmt.Errorf("failed to remove subscription: %w", err)
+		}
+
+		if !removed {
+			return b.sendText(ctx, chatID, localize(lang, msgNotWatching, channel))
+		}
+		return b.sendText(ctx, chatID, localize(lang, msgUnwatched, channel))
+	}
+}
+
+// ViewCmdWatchlist creates a view handler for the /watchlist command.
+// It lists the chat's subscriptions.
+//
+// Returns:
+//
+//	A ViewFunc that handles the watchlist command interaction
+func (b *Bot) ViewCmdWatchlist() ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		lang := messageLang(update.Message)
+		chatID := update.Message.Chat.ID
+
+		subs, err := b.watcher.subs.Subscriptions(chatID)
+		if err != nil {
+			return fmt.Errorf("failed to load subscriptions: %w", err)
+		}
+
+		if len(subs) == 0 {
+			return b.sendText(ctx, chatID, localize(lang, msgWatchlistEmpty))
+		}
+
+		text := localize(lang, msgWatchlistHeader) + "\n"
+		for i, sub := range subs {
+			text += fmt.Sprintf("%d. %s: %s\n", i+1, sub.Channel, joinLists(sub.Lists))
+		}
+
+		return b.sendText(ctx, chatID, text)
+	}
+}
+
+// parseWatchArgs parses "<channel> [mods] [vips] [founders]".
+//
+// Parameters:
+//
+//	args - Command arguments
+//
+// Returns:
+//
+//	The channel as typed, the lists to watch and whether the arguments are valid
+func parseWatchArgs(args string) (string, []fetcher.Endpoint, bool) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return "", nil, false
+	}
+
+	selected := make(map[fetcher.Endpoint]bool)
+	for _, field := range fields[1:] {
+		list, ok := listAliases[strings.ToLower(field)]
+		if !ok || list == fetcher.EndpointFollows {
+			return "", nil, false
+		}
+		selected[list] = true
+	}
+
+	var lists []fetcher.Endpoint
+	for _, list := range watchableLists {
+		if len(selected) == 0 || selected[list] {
+			lists = append(lists, list)
+		}
+	}
+
+	return fields[0], lists, true
+}
+
+// isSubscribed reports whether subs contains a subscription to channel.
+func isSubscribed(subs []storage.Subscription, channel string) bool {
+	for _, sub := range subs {
+		if sub.Channel == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// joinLists renders list types as a comma-separated string.
+func joinLists(lists []fetcher.Endpoint) string {
+	names := make([]string, len(lists))
+	for i, list := range lists {
+		names[i] = string(list)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kirinyoku/twitch-kit/internal/diff"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/formatter"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
+)
+
+// SubscriptionStore defines an interface for persisting watchlist subscriptions.
+type SubscriptionStore interface {
+	Subscribe(sub storage.Subscription) error
+	Unsubscribe(chatID int64, channel string) (bool, error)
+	Subscriptions(chatID int64) ([]storage.Subscription, error)
+	AllSubscriptions() ([]storage.Subscription, error)
+}
+
+// watchPollTimeout bounds a single channel list poll.
+const watchPollTimeout = 30 * time.Second
+
+// watchableLists lists the list types that can be watched, in display order.
+var watchableLists = []fetcher.Endpoint{
+	fetcher.EndpointMods,
+	fetcher.EndpointVips,
+	fetcher.EndpointFounders,
+}
+
+// watchKey identifies a polled channel list shared by all its subscribers.
+type watchKey struct {
+	channel string
+	list    fetcher.Endpoint
+}
+
+// watcher polls watched channel lists and remembers the last version it saw of each.
+type watcher struct {
+	subs     SubscriptionStore // Persisted subscriptions
+	interval time.Duration     // Time between polls
+	maxSubs  int               // Maximum subscriptions per chat, zero for unlimited
+	lastSeen map[watchKey]any  // Last polled list per channel, holding a []T
+}
+
+// runWatcher polls all subscribed channel lists until the context is done.
+//
+// Parameters:
+//
+//	ctx - Context controlling the watcher's lifecycle
+func (b *Bot) runWatcher(ctx context.Context) {
+	ticker := time.NewTicker(b.watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		// The next poll is attempted even if this one panicked.
+		_ = b.guard(ctx, "watchlist poll", func() error {
+			b.pollWatchlist(ctx)
+			return nil
+		})
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollWatchlist fetches every subscribed channel list once, regardless of how
+// many chats watch it, and notifies the subscribers of any changes.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+func (b *Bot) pollWatchlist(ctx context.Context) {
+	const op = "bot.pollWatchlist"
+
+	subs, err := b.watcher.subs.AllSubscriptions()
+	if err != nil {
+		log.Printf("%s: %v", op, err)
+		return
+	}
+
+	subscribers := make(map[watchKey][]int64)
+	for _, sub := range subs {
+		for _, list := range sub.Lists {
+			key := watchKey{channel: sub.Channel, list: list}
+			subscribers[key] = append(subscribers[key], sub.ChatID)
+		}
+	}
+
+	for key, chatIDs := range subscribers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A list that makes the poll panic must not keep the others from being checked.
+		err := b.guard(ctx, fmt.Sprintf("watchlist poll of %s %s", key.channel, key.list), func() error {
+			return b.notifyWatchers(ctx, key, chatIDs)
+		})
+		if err != nil {
+			log.Printf("%s: %s %s: %v", op, key.list, key.channel, err)
+		}
+	}
+
+	for key := range b.watcher.lastSeen {
+		if _, ok := subscribers[key]; !ok {
+			delete(b.watcher.lastSeen, key)
+		}
+	}
+}
+
+// notifyWatchers polls a channel list and notifies its subscribers if users were added or removed.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	key - Channel list to poll
+//	chatIDs - Chats watching the list
+//
+// Returns:
+//
+//	An error if the list could not be polled
+func (b *Bot) notifyWatchers(ctx context.Context, key watchKey, chatIDs []int64) error {
+	const op = "bot.notifyWatchers"
+
+	result, changed, err := b.pollList(ctx, key)
+	if err != nil || !changed {
+		return err
+	}
+
+	text := formatter.FormatWatchNotification(result)
+	for _, chatID := range chatIDs {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = tgbotapi.ModeHTML
+		msg.DisableWebPagePreview = true
+		if _, err := b.send(ctx, chatID, msg); err != nil {
+			log.Printf("%s: failed to notify chat %d: %v", op, chatID, err)
+		}
+	}
+
+	return nil
+}
+
+// pollList fetches a single channel list and compares it with the last version seen.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	key - Channel list to poll
+//
+// Returns:
+//
+//	The diff result, whether users were added or removed and an error if any
+func (b *Bot) pollList(ctx context.Context, key watchKey) (diff.Result, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, watchPollTimeout)
+	defer cancel()
+
+	switch key.list {
+	case fetcher.EndpointMods:
+		return pollWatched(ctx, b, key, b.pollFetch.FetchMods, diff.Mods)
+	case fetcher.EndpointVips:
+		return pollWatched(ctx, b, key, b.pollFetch.FetchVips, diff.Vips)
+	case fetcher.EndpointFounders:
+		return pollWatched(ctx, b, key, b.pollFetch.FetchFounders, diff.Founders)
+	}
+
+	return diff.Result{}, false, nil
+}
+
+// pollWatched fetches the current list and diffs it against the previous poll.
+// The first poll after startup is compared with the newest stored snapshot, if any.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	b - Bot instance
+//	key - Channel list to poll
+//	fetch - Fetch function for the list type
+//	compare - Diff function for the list type
+//
+// Returns:
+//
+//	The diff result, whether users were added or removed and an error if any
+func pollWatched[T any](ctx context.Context, b *Bot, key watchKey, fetch func(context.Context, string) ([]T, error), compare func(older, newer []T) diff.Result) (diff.Result, bool, error) {
+	current, err := fetch(ctx, key.channel)
+	if err != nil && !errors.Is(err, fetcher.ErrEmptyList) {
+		return diff.Result{}, false, err
+	}
+
+	previous, ok := b.watcher.lastSeen[key].([]T)
+	if !ok && b.history != nil {
+		if snap, found, err := b.history.At(key.channel, key.list, time.Now().Add(-b.watcher.interval)); err == nil && found {
+			previous, ok = decodeSnapshot[T](snap)
+		}
+	}
+
+	b.watcher.lastSeen[key] = current
+	if !ok {
+		return diff.Result{}, false, nil
+	}
+
+	result := compare(previous, current)
+	result.Channel = key.channel
+	result.List = key.list
+	result.To = time.Now()
+
+	return result, len(result.Added) > 0 || len(result.Removed) > 0, nil
+}
+
+// decodeSnapshot decodes a stored snapshot, reporting whether decoding succeeded.
+func decodeSnapshot[T any](snap storage.Snapshot) ([]T, bool) {
+	items, err := storage.Decode[T](snap)
+	return items, err == nil
+}
@@ -0,0 +1,227 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	secretTokenHeader    = "X-Telegram-Bot-Api-Secret-Token" // Header carrying the webhook secret token
+	maxWebhookBodySize   = 1 << 20                           // Maximum size of a webhook request body
+	webhookReadTimeout   = 10 * time.Second                  // Time limit for reading a webhook request
+	webhookStopTimeout   = 5 * time.Second                   // Time limit for finishing webhook requests on shutdown
+	defaultWebhookListen = ":8443"                           // Default address of the webhook server
+)
+
+// WebhookConfig configures receiving updates through a webhook instead of long polling.
+type WebhookConfig struct {
+	URL            string // Public HTTPS base URL Telegram sends updates to
+	Listen         string // Address of the embedded HTTP server, e.g. ":8443"
+	PathSecret     string // Secret path segment the webhook is served under, empty for "/"
+	SecretToken    string // Expected X-Telegram-Bot-Api-Secret-Token value, empty to skip the check
+	CertFile       string // TLS certificate file, empty to serve plain HTTP behind a reverse proxy
+	KeyFile        string // TLS private key file
+	UploadCert     bool   // Whether the certificate is uploaded to Telegram, needed for self-signed ones
+	MaxConnections int    // Maximum simultaneous connections from Telegram, zero for its default
+}
+
+// path returns the URL path the webhook is served under.
+func (c WebhookConfig) path() string {
+	return "/" + c.PathSecret
+}
+
+// updateSource delivers updates received from Telegram.
+type updateSource struct {
+	updates <-chan tgbotapi.Update // Received updates
+	errs    <-chan error           // Errors that stop receiving, nil if receiving cannot fail
+	stop    func()                 // Stops receiving updates
+}
+
+// receiveUpdates starts receiving updates through the webhook if one is configured,
+// and through long polling otherwise.
+//
+// Returns:
+//
+//	The update source and an error if the webhook could not be registered
+func (b *Bot) receiveUpdates() (updateSource, error) {
+	if b.webhook != nil {
+		return b.serveWebhook(*b.webhook)
+	}
+	return b.pollUpdates(), nil
+}
+
+// pollUpdates starts receiving updates through long polling.
+// A previously registered webhook is removed, since Telegram rejects polling while one is set.
+//
+// Returns:
+//
+//	The update source
+func (b *Bot) pollUpdates() updateSource {
+	const op = "bot.pollUpdates"
+
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Printf("%s: failed to delete webhook: %v", op, err)
+	}
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	return updateSource{
+		updates: b.api.GetUpdatesChan(u),
+		stop:    b.api.StopReceivingUpdates,
+	}
+}
+
+// serveWebhook registers the webhook with Telegram and starts the embedded HTTP server.
+// The webhook is left registered on shutdown, so other replicas keep receiving updates.
+//
+// Parameters:
+//
+//	cfg - Webhook configuration
+//
+// Returns:
+//
+//	The update source and an error if the webhook could not be registered or the server could not listen
+func (b *Bot) serveWebhook(cfg WebhookConfig) (updateSource, error) {
+	const op = "bot.serveWebhook"
+
+	listen := cfg.Listen
+	if listen == "" {
+		listen = defaultWebhookListen
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return updateSource{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := b.setWebhook(cfg); err != nil {
+		listener.Close()
+		return updateSource{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updates := make(chan tgbotapi.Update)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+
+	mux := http.NewServeMux()
+	pattern := cfg.path()
+	if pattern == "/" {
+		// Without a path secret only the root is served, not every path.
+		pattern = "/{$}"
+	}
+	mux.Handle("POST "+pattern, webhookHandler(cfg.SecretToken, updates, done))
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: webhookReadTimeout,
+		ReadTimeout:       webhookReadTimeout,
+	}
+
+	go func() {
+		var err error
+		if cfg.CertFile != "" {
+			err = server.ServeTLS(listener, cfg.CertFile, cfg.KeyFile)
+		} else {
+			err = server.Serve(listener)
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- fmt.Errorf("%s: %w", op, err)
+		}
+	}()
+
+	log.Printf("%s: listening on %s", op, listener.Addr())
+
+	stop := func() {
+		close(done)
+		ctx, cancel := context.WithTimeout(context.Background(), webhookStopTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("%s: failed to stop server: %v", op, err)
+		}
+	}
+
+	return updateSource{updates: updates, errs: errs, stop: stop}, nil
+}
+
+// setWebhook registers the webhook URL and secret token with Telegram.
+// The request is built by hand because the API client has no secret token field.
+//
+// Parameters:
+//
+//	cfg - Webhook configuration
+//
+// Returns:
+//
+//	An error if Telegram rejects the webhook
+func (b *Bot) setWebhook(cfg WebhookConfig) error {
+	params := make(tgbotapi.Params)
+	params["url"] = strings.TrimSuffix(cfg.URL, "/") + cfg.path()
+	params.AddNonEmpty("secret_token", cfg.SecretToken)
+	params.AddNonZero("max_connections", cfg.MaxConnections)
+
+	var err error
+	if cfg.UploadCert && cfg.CertFile != "" {
+		_, err = b.api.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{{
+			Name: "certificate",
+			Data: tgbotapi.FilePath(cfg.CertFile),
+		}})
+	} else {
+		_, err = b.api.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	return nil
+}
+
+// webhookHandler creates the HTTP handler receiving updates from Telegram.
+// A request is answered once its update is accepted, so a full queue slows Telegram down
+// instead of dropping updates.
+//
+// Parameters:
+//
+//	secretToken - Expected X-Telegram-Bot-Api-Secret-Token value, empty to skip the check
+//	updates - Channel the decoded updates are sent to
+//	done - Closed when updates are no longer accepted
+//
+// Returns:
+//
+//	The HTTP handler
+func webhookHandler(secretToken string, updates chan<- tgbotapi.Update, done <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "bot.webhookHandler"
+
+		if secretToken != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), []byte(secretToken)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodySize)).Decode(&update); err != nil {
+			log.Printf("%s: invalid update: %v", op, err)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case updates <- update:
+			w.WriteHeader(http.StatusOK)
+		case <-done:
+			// Telegram retries updates that were not acknowledged.
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+		}
+	})
+}
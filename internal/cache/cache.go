@@ -0,0 +1,234 @@
+// Package cache provides an in-memory caching decorator for Twitch data fetchers.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// fetchTimeout bounds an upstream call shared by deduplicated lookups, which
+// runs independently of the context of the lookup that started it.
+const fetchTimeout = time.Minute
+
+// Fetcher defines the interface for fetching Twitch channel data that the cache wraps.
+type Fetcher interface {
+	FetchFollows(ctx context.Context, username string) ([]fetcher.Follow, error)
+	FetchMods(ctx context.Context, username string) ([]fetcher.Mod, error)
+	FetchVips(ctx context.Context, username string) ([]fetcher.Vip, error)
+	FetchFounders(ctx context.Context, username string) ([]fetcher.Founders, error)
+}
+
+// Stats reports cache effectiveness counters. A miss means the result was not
+// in the cache, not that it was fetched from Twitch: the wrapped fetcher may
+// answer it from elsewhere, such as a recent stored snapshot.
+type Stats struct {
+	Hits    uint64 // Lookups served from the cache
+	Misses  uint64 // Calls to the wrapped fetcher, concurrent identical lookups count once
+	Shared  uint64 // Lookups that joined an upstream call already in flight
+	Entries int    // Entries currently stored
+}
+
+// Cache is a Fetcher decorator that keeps results in memory for a limited time,
+// evicts the least recently used entries when full, and collapses concurrent
+// identical requests into a single upstream call.
+type Cache struct {
+	next       Fetcher       // Wrapped fetcher
+	ttl        time.Duration // Lifetime of a cached result
+	maxEntries int           // Maximum number of stored results, zero for unlimited
+	mu         sync.Mutex    // Guards entries and order
+	entries    map[string]*list.Element
+	order      *list.List         // Entries ordered from most to least recently used
+	group      singleflight.Group // Deduplicates concurrent misses
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	shared     atomic.Uint64
+	now        func() time.Time // Clock used for expiry
+}
+
+// entry is a single cached result.
+type entry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// New creates a new Cache wrapping the provided fetcher.
+//
+// Parameters:
+//
+//	next - Fetcher to delegate misses to
+//	ttl - Lifetime of a cached result
+//	maxEntries - Maximum number of stored results, zero for unlimited
+//
+// Returns:
+//
+//	A pointer to a new Cache instance
+func New(next Fetcher, ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// FetchFollows returns the cached follows of a user or fetches them from the wrapped fetcher.
+func (c *Cache) FetchFollows(ctx context.Context, username string) ([]fetcher.Follow, error) {
+	return get(ctx, c, fetcher.EndpointFollows, username, c.next.FetchFollows)
+}
+
+// FetchMods returns the cached moderators of a channel or fetches them from the wrapped fetcher.
+func (c *Cache) FetchMods(ctx context.Context, username string) ([]fetcher.Mod, error) {
+	return get(ctx, c, fetcher.EndpointMods, username, c.next.FetchMods)
+}
+
+// FetchVips returns the cached VIPs of a channel or fetches them from the wrapped fetcher.
+func (c *Cache) FetchVips(ctx context.Context, username string) ([]fetcher.Vip, error) {
+	return get(ctx, c, fetcher.EndpointVips, username, c.next.FetchVips)
+}
+
+// FetchFounders returns the cached founders of a channel or fetches them from the wrapped fetcher.
+func (c *Cache) FetchFounders(ctx context.Context, username string) ([]fetcher.Founders, error) {
+	return get(ctx, c, fetcher.EndpointFounders, username, c.next.FetchFounders)
+}
+
+// Stats returns a snapshot of the cache counters.
+//
+// Returns:
+//
+//	Current hit, miss and entry counts
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Shared:  c.shared.Load(),
+		Entries: entries,
+	}
+}
+
+// get serves a lookup from the cache or performs a deduplicated upstream call.
+// Callers receive their own copy of the slice so they may reorder it freely.
+// The upstream call is not cancelled with the lookup that started it, so the
+// other lookups waiting for it are unaffected; each lookup still returns as
+// soon as its own context is done.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	c - Cache instance
+//	endpoint - Endpoint the result belongs to
+//	username - Twitch username, normalized for the cache key
+//	fetch - Upstream fetch function used on a miss
+//
+// Returns:
+//
+//	A slice of items and an error if any
+func get[T any](ctx context.Context, c *Cache, endpoint fetcher.Endpoint, username string, fetch func(context.Context, string) ([]T, error)) ([]T, error) {
+	key := string(endpoint) + ":" + utils.NormalizeUsername(username)
+
+	if value, ok := c.load(key); ok {
+		c.hits.Add(1)
+		return slices.Clone(value.([]T)), nil
+	}
+
+	started := false
+	ch := c.group.DoChan(key, func() (any, error) {
+		started = true
+		c.misses.Add(1)
+
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+
+		items, err := fetch(fetchCtx, username)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, items)
+		return items, nil
+	})
+
+	select {
+	case res := <-ch:
+		if !started {
+			c.shared.Add(1)
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return slices.Clone(res.Val.([]T)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load returns an unexpired cached value and marks it as recently used.
+//
+// Parameters:
+//
+//	key - Cache key
+//
+// Returns:
+//
+//	The cached value and true, or nil and false on a miss
+func (c *Cache) load(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if c.now().After(e.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// store saves a value and evicts the least recently used entries beyond maxEntries.
+//
+// Parameters:
+//
+//	key - Cache key
+//	value - Value to store
+func (c *Cache) store(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+}
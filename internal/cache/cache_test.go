@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// fakeFetcher returns one mod named after the channel and counts its calls.
+type fakeFetcher struct {
+	mu      sync.Mutex
+	calls   []string      // Channels fetched, in call order
+	release chan struct{} // Blocks calls until closed, if set
+	err     error
+}
+
+func (f *fakeFetcher) FetchMods(ctx context.Context, username string) ([]fetcher.Mod, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, username)
+	f.mu.Unlock()
+
+	if f.release != nil {
+		<-f.release
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []fetcher.Mod{{Login: username}, {Login: username + "_2"}}, nil
+}
+
+func (f *fakeFetcher) FetchFollows(ctx context.Context, username string) ([]fetcher.Follow, error) {
+	return nil, nil
+}
+
+func (f *fakeFetcher) FetchVips(ctx context.Context, username string) ([]fetcher.Vip, error) {
+	return nil, nil
+}
+
+func (f *fakeFetcher) FetchFounders(ctx context.Context, username string) ([]fetcher.Founders, error) {
+	return nil, nil
+}
+
+func (f *fakeFetcher) fetched() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	tests := []struct {
+		name    string
+		lookups []string
+		want    []string // Channels fetched from the wrapped fetcher
+	}{
+		{name: "repeated lookups hit", lookups: []string{"a", "a", "A"}, want: []string{"a"}},
+		{name: "fits without eviction", lookups: []string{"a", "b", "a", "b"}, want: []string{"a", "b"}},
+		{name: "oldest entry is evicted", lookups: []string{"a", "b", "c", "a"}, want: []string{"a", "b", "c", "a"}},
+		{name: "a hit keeps an entry", lookups: []string{"a", "b", "a", "c", "a", "b"}, want: []string{"a", "b", "c", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &fakeFetcher{}
+			c := New(next, time.Hour, 2)
+
+			for _, channel := range tt.lookups {
+				if _, err := c.FetchMods(context.Background(), channel); err != nil {
+					t.Fatalf("FetchMods(%q): %v", channel, err)
+				}
+			}
+
+			if got := next.fetched(); !slices.Equal(got, tt.want) {
+				t.Errorf("fetched %v, want %v", got, tt.want)
+			}
+			stats := c.Stats()
+			if want := uint64(len(tt.lookups) - len(tt.want)); stats.Hits != want || stats.Misses != uint64(len(tt.want)) {
+				t.Errorf("%d hits and %d misses, want %d and %d", stats.Hits, stats.Misses, want, len(tt.want))
+			}
+			if stats.Entries > 2 {
+				t.Errorf("%d entries, want at most 2", stats.Entries)
+			}
+		})
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	next := &fakeFetcher{}
+	c := New(next, time.Minute, 0)
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return clock }
+
+	lookup := func() {
+		t.Helper()
+		if _, err := c.FetchMods(context.Background(), "xqc"); err != nil {
+			t.Fatalf("FetchMods: %v", err)
+		}
+	}
+
+	lookup()
+	clock = clock.Add(time.Minute)
+	lookup()
+	if got := len(next.fetched()); got != 1 {
+		t.Fatalf("fetched %d times within the TTL, want once", got)
+	}
+
+	clock = clock.Add(time.Second)
+	lookup()
+	if got := len(next.fetched()); got != 2 {
+		t.Errorf("fetched %d times after the TTL, want twice", got)
+	}
+}
+
+func TestCacheSharesConcurrentMisses(t *testing.T) {
+	const lookups = 5
+
+	next := &fakeFetcher{release: make(chan struct{})}
+	c := New(next, time.Hour, 0)
+
+	var wg sync.WaitGroup
+	results := make([][]fetcher.Mod, lookups)
+	for i := range lookups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mods, err := c.FetchMods(context.Background(), "xqc")
+			if err != nil {
+				t.Errorf("lookup %d: %v", i, err)
+			}
+			results[i] = mods
+		}()
+	}
+
+	// Let every lookup reach the upstream call before it returns.
+	for len(next.fetched()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	if got := len(next.fetched()); got != 1 {
+		t.Errorf("fetched %d times, want once", got)
+	}
+	if stats := c.Stats(); stats.Misses != 1 || stats.Shared != lookups-1 {
+		t.Errorf("%d misses and %d shared, want 1 and %d", stats.Misses, stats.Shared, lookups-1)
+	}
+
+	// Every caller gets its own copy.
+	results[0][0].Login = "changed"
+	for i, mods := range results[1:] {
+		if len(mods) != 2 || mods[0].Login != "xqc" {
+			t.Errorf("lookup %d got %v", i+1, mods)
+		}
+	}
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	next := &fakeFetcher{err: errors.New("upstream down")}
+	c := New(next, time.Hour, 0)
+
+	for range 2 {
+		if _, err := c.FetchMods(context.Background(), "xqc"); err == nil {
+			t.Fatal("FetchMods succeeded, want the upstream error")
+		}
+	}
+	if got := len(next.fetched()); got != 2 {
+		t.Errorf("fetched %d times, want every failed lookup retried", got)
+	}
+	if stats := c.Stats(); stats.Entries != 0 {
+		t.Errorf("%d entries, want none", stats.Entries)
+	}
+}
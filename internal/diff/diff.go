@@ -0,0 +1,243 @@
+// Package diff compares stored snapshots of Twitch channel lists.
+package diff
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/storage"
+)
+
+// Entry identifies a user in a list.
+type Entry struct {
+	ID          string
+	Login       string
+	DisplayName string
+}
+
+// FlagChange describes a boolean attribute that flipped between two snapshots.
+type FlagChange struct {
+	Field string // Attribute name, e.g. "banned"
+	Old   bool   // Value in the older snapshot
+	New   bool   // Value in the newer snapshot
+}
+
+// Changed is a user present in both snapshots whose attributes differ.
+type Changed struct {
+	Entry
+	Changes []FlagChange
+}
+
+// Result holds the differences between two versions of a channel list.
+type Result struct {
+	Channel string           // Twitch channel name
+	List    fetcher.Endpoint // List type that was compared
+	Since   time.Time        // Requested start of the period, before From if history begins later
+	From    time.Time        // Time of the older version
+	To      time.Time        // Time of the newer version
+	Added   []Entry          // Users present only in the newer version
+	Removed []Entry          // Users present only in the older version
+	Changed []Changed        // Users present in both versions with flipped flags
+}
+
+// Empty reports whether the two versions are identical.
+//
+// Returns:
+//
+//	True if nothing was added, removed or changed
+func (r Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// flag is a named boolean attribute of a list entry.
+type flag struct {
+	name  string
+	value bool
+}
+
+// Follows compares two versions of a follow list.
+//
+// Parameters:
+//
+//	older - Older version of the list
+//	newer - Newer version of the list
+//
+// Returns:
+//
+//	A Result with added, removed and changed entries
+func Follows(older, newer []fetcher.Follow) Result {
+	return compare(older, newer, func(f fetcher.Follow) (Entry, []flag) {
+		return Entry{f.ID, f.Login, f.DisplayName}, []flag{{"live", f.IsLive}}
+	})
+}
+
+// Mods compares two versions of a moderator list.
+//
+// Parameters:
+//
+//	older - Older version of the list
+//	newer - Newer version of the list
+//
+// Returns:
+//
+//	A Result with added, removed and changed entries
+func Mods(older, newer []fetcher.Mod) Result {
+	return compare(older, newer, func(m fetcher.Mod) (Entry, []flag) {
+		return Entry{m.ID, m.Login, m.DisplayName}, []flag{{"banned", m.Banned}}
+	})
+}
+
+// Vips compares two versions of a VIP list.
+//
+// Parameters:
+//
+//	older - Older version of the list
+//	newer - Newer version of the list
+//
+// Returns:
+//
+//	A Result with added, removed and changed entries
+func Vips(older, newer []fetcher.Vip) Result {
+	return compare(older, newer, func(v fetcher.Vip) (Entry, []flag) {
+		return Entry{v.ID, v.Login, v.DisplayName}, []flag{{"banned", v.Banned}}
+	})
+}
+
+// Founders compares two versions of a founder list.
+//
+// Parameters:
+//
+//	older - Older version of the list
+//	newer - Newer version of the list
+//
+// Returns:
+//
+//	A Result with added, removed and changed entries
+func Founders(older, newer []fetcher.Founders) Result {
+	return compare(older, newer, func(f fetcher.Founders) (Entry, []flag) {
+		return Entry{f.ID, f.Login, f.DisplayName}, []flag{{"subscribed", f.IsSubscribed}, {"banned", f.Banned}}
+	})
+}
+
+// Snapshots compares two stored snapshots of the same channel list.
+//
+// Parameters:
+//
+//	older - Older snapshot
+//	newer - Newer snapshot
+//
+// Returns:
+//
+//	A Result covering the snapshots' time range and an error if they cannot be decoded
+func Snapshots(older, newer storage.Snapshot) (Result, error) {
+	var (
+		result Result
+		err    error
+	)
+
+	switch newer.List {
+	case fetcher.EndpointFollows:
+		result, err = decodeAndCompare(older, newer, Follows)
+	case fetcher.EndpointMods:
+		result, err = decodeAndCompare(older, newer, Mods)
+	case fetcher.EndpointVips:
+		result, err = decodeAndCompare(older, newer, Vips)
+	case fetcher.EndpointFounders:
+		result, err = decodeAndCompare(older, newer, Founders)
+	default:
+		return Result{}, fmt.Errorf("diff.Snapshots: unsupported list type: %s", newer.List)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Channel = newer.Channel
+	result.List = newer.List
+	result.From = older.TakenAt
+	result.To = newer.TakenAt
+
+	return result, nil
+}
+
+// decodeAndCompare decodes both snapshots into T and compares them.
+func decodeAndCompare[T any](older, newer storage.Snapshot, compare func(older, newer []T) Result) (Result, error) {
+	oldItems, err := storage.Decode[T](older)
+	if err != nil {
+		return Result{}, err
+	}
+
+	newItems, err := storage.Decode[T](newer)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return compare(oldItems, newItems), nil
+}
+
+// compare matches entries of two list versions by ID (falling back to login)
+// and collects additions, removals and flipped flags in list order.
+//
+// Parameters:
+//
+//	older - Older version of the list
+//	newer - Newer version of the list
+//	describe - Extracts the identity and flags of an item
+//
+// Returns:
+//
+//	A Result with added, removed and changed entries
+func compare[T any](older, newer []T, describe func(T) (Entry, []flag)) Result {
+	type described struct {
+		entry Entry
+		flags []flag
+	}
+
+	previous := make(map[string]described, len(older))
+	for _, item := range older {
+		entry, flags := describe(item)
+		previous[key(entry)] = described{entry, flags}
+	}
+
+	var result Result
+	seen := make(map[string]bool, len(newer))
+
+	for _, item := range newer {
+		entry, flags := describe(item)
+		k := key(entry)
+		seen[k] = true
+
+		old, ok := previous[k]
+		if !ok {
+			result.Added = append(result.Added, entry)
+			continue
+		}
+
+		var changes []FlagChange
+		for i, f := range flags {
+			if i < len(old.flags) && old.flags[i].value != f.value {
+				changes = append(changes, FlagChange{Field: f.name, Old: old.flags[i].value, New: f.value})
+			}
+		}
+		if len(changes) > 0 {
+			result.Changed = append(result.Changed, Changed{Entry: entry, Changes: changes})
+		}
+	}
+
+	for _, item := range older {
+		entry, _ := describe(item)
+		if !seen[key(entry)] {
+			result.Removed = append(result.Removed, entry)
+		}
+	}
+
+	return result
+}
+
+// key returns the identity used to match entries across versions.
+func key(e Entry) string {
+	if e.ID != "" {
+		return e.ID
+	}
+	return e.Login
+}
@@ -0,0 +1,230 @@
+// Package export renders Twitch channel lists as CSV, JSON and XLSX documents.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// Format is a document format.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatXLSX Format = "xlsx"
+)
+
+// Formats lists the supported formats in the order they are offered to users.
+var Formats = []Format{FormatCSV, FormatJSON, FormatXLSX}
+
+// Columns are the column names shared by every format and list type.
+var Columns = []string{"id", "login", "display_name", "avatar_url", "date", "live", "banned", "subscribed"}
+
+// timestampLayout is the time format used in file names.
+const timestampLayout = "20060102-150405"
+
+// Row is a list entry with the columns shared by all list types.
+// Flags that do not apply to a list type are nil and exported as empty cells.
+type Row struct {
+	ID          string    `json:"id"`
+	Login       string    `json:"login"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+	Date        time.Time `json:"date,omitzero"` // Follow, grant or first subscription date, in UTC with second precision
+	Live        *bool     `json:"live,omitempty"`
+	Banned      *bool     `json:"banned,omitempty"`
+	Subscribed  *bool     `json:"subscribed,omitempty"`
+}
+
+// cells returns the row's values in column order.
+func (r Row) cells() []string {
+	flag := func(value *bool) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatBool(*value)
+	}
+
+	date := ""
+	if !r.Date.IsZero() {
+		date = r.Date.Format(time.RFC3339)
+	}
+
+	return []string{r.ID, r.Login, r.DisplayName, r.AvatarURL, date, flag(r.Live), flag(r.Banned), flag(r.Subscribed)}
+}
+
+// ParseFormat converts a user-typed format name into a Format.
+//
+// Parameters:
+//
+//	name - Format name, e.g. "csv"
+//
+// Returns:
+//
+//	The format and false if it is not supported
+func ParseFormat(name string) (Format, bool) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// Write renders rows in a format.
+//
+// Parameters:
+//
+//	w - Destination of the document
+//	format - Document format
+//	rows - Rows to write
+//
+// Returns:
+//
+//	An error if the format is unknown or writing fails
+func Write(w io.Writer, format Format, rows []Row) error {
+	const op = "export.Write"
+
+	var err error
+	switch format {
+	case FormatCSV:
+		err = writeCSV(w, rows)
+	case FormatJSON:
+		err = writeJSON(w, rows)
+	case FormatXLSX:
+		err = writeXLSX(w, rows)
+	default:
+		err = fmt.Errorf("unknown format: %s", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Filename builds a document name from the channel, list type and export time,
+// e.g. "xqc_mods_20250110-153000.csv".
+//
+// Parameters:
+//
+//	channel - Twitch channel name
+//	list - Exported list type
+//	at - Export time
+//	format - Document format
+//
+// Returns:
+//
+//	The file name
+func Filename(channel string, list fetcher.Endpoint, at time.Time, format Format) string {
+	return fmt.Sprintf("%s_%s_%s.%s", channel, list, at.UTC().Format(timestampLayout), format)
+}
+
+// writeCSV writes a header row followed by the rows.
+func writeCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row.cells()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeJSON writes the rows as an indented JSON array.
+func writeJSON(w io.Writer, rows []Row) error {
+	if rows == nil {
+		rows = []Row{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+// normalizeTime converts a date to UTC with second precision, so all formats agree.
+func normalizeTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC().Truncate(time.Second)
+}
+
+// Follows converts a follow list into rows.
+//
+// Parameters:
+//
+//	follows - Follow list
+//
+// Returns:
+//
+//	The rows in list order
+func Follows(follows []fetcher.Follow) []Row {
+	rows := make([]Row, 0, len(follows))
+	for _, f := range follows {
+		rows = append(rows, Row{ID: f.ID, Login: f.Login, DisplayName: f.DisplayName, AvatarURL: f.Avatar, Date: normalizeTime(f.FollowedAt), Live: &f.IsLive})
+	}
+	return rows
+}
+
+// Mods converts a moderator list into rows.
+//
+// Parameters:
+//
+//	mods - Moderator list
+//
+// Returns:
+//
+//	The rows in list order
+func Mods(mods []fetcher.Mod) []Row {
+	rows := make([]Row, 0, len(mods))
+	for _, m := range mods {
+		rows = append(rows, Row{ID: m.ID, Login: m.Login, DisplayName: m.DisplayName, AvatarURL: m.Avatar, Date: normalizeTime(m.GrantedAt), Banned: &m.Banned})
+	}
+	return rows
+}
+
+// Vips converts a VIP list into rows.
+//
+// Parameters:
+//
+//	vips - VIP list
+//
+// Returns:
+//
+//	The rows in list order
+func Vips(vips []fetcher.Vip) []Row {
+	rows := make([]Row, 0, len(vips))
+	for _, v := range vips {
+		rows = append(rows, Row{ID: v.ID, Login: v.Login, DisplayName: v.DisplayName, AvatarURL: v.Avatar, Date: normalizeTime(v.GrantedAt), Banned: &v.Banned})
+	}
+	return rows
+}
+
+// Founders converts a founder list into rows.
+//
+// Parameters:
+//
+//	founders - Founder list
+//
+// Returns:
+//
+//	The rows in list order
+func Founders(founders []fetcher.Founders) []Row {
+	rows := make([]Row, 0, len(founders))
+	for _, f := range founders {
+		rows = append(rows, Row{ID: f.ID, Login: f.Login, DisplayName: f.DisplayName, AvatarURL: f.Avatar, Date: normalizeTime(f.FirstMonth), Banned: &f.Banned, Subscribed: &f.IsSubscribed})
+	}
+	return rows
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// Static parts of a single-sheet workbook. Cells use inline strings, so no
+// shared string table or styles are needed.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="List" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+)
+
+// xlsxWorksheet is the XML document of a worksheet.
+type xlsxWorksheet struct {
+	XMLName xml.Name  `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main worksheet"`
+	Rows    []xlsxRow `xml:"sheetData>row"`
+}
+
+// xlsxRow is a worksheet row.
+type xlsxRow struct {
+	Index int        `xml:"r,attr"`
+	Cells []xlsxCell `xml:"c"`
+}
+
+// xlsxCell is a worksheet cell holding an inline string or a boolean.
+type xlsxCell struct {
+	Ref    string      `xml:"r,attr"`
+	Type   string      `xml:"t,attr"`
+	Value  string      `xml:"v,omitempty"`
+	Inline *xlsxInline `xml:"is,omitempty"`
+}
+
+// xlsxInline is the content of an inline string cell.
+type xlsxInline struct {
+	Text string `xml:"t"`
+}
+
+// writeXLSX writes the rows as a single-sheet workbook with a header row.
+func writeXLSX(w io.Writer, rows []Row) error {
+	sheet := xlsxWorksheet{Rows: []xlsxRow{xlsxStringRow(1, Columns)}}
+	for i, row := range rows {
+		sheet.Rows = append(sheet.Rows, xlsxDataRow(i+2, row))
+	}
+
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(f).Encode(sheet); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// xlsxStringRow builds a row of inline strings, skipping empty values.
+func xlsxStringRow(index int, values []string) xlsxRow {
+	row := xlsxRow{Index: index}
+	for col, value := range values {
+		if value == "" {
+			continue
+		}
+		row.Cells = append(row.Cells, xlsxCell{
+			Ref:    cellRef(col, index),
+			Type:   "inlineStr",
+			Inline: &xlsxInline{Text: value},
+		})
+	}
+	return row
+}
+
+// xlsxDataRow builds a row with the flag columns stored as booleans.
+func xlsxDataRow(index int, r Row) xlsxRow {
+	cells := r.cells()
+	row := xlsxStringRow(index, cells[:5])
+
+	for i, flag := range []*bool{r.Live, r.Banned, r.Subscribed} {
+		if flag == nil {
+			continue
+		}
+		value := "0"
+		if *flag {
+			value = "1"
+		}
+		row.Cells = append(row.Cells, xlsxCell{Ref: cellRef(5+i, index), Type: "b", Value: value})
+	}
+
+	return row
+}
+
+// cellRef returns the A1-style reference of a zero-based column and one-based row.
+func cellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
@@ -0,0 +1,64 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors returned (wrapped) by the Fetch* methods. Use errors.Is to branch on them.
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrEmptyList           = errors.New("empty list")
+	ErrRateLimited         = errors.New("rate limited by upstream")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// maxErrorBodySize limits how much of an error response body is kept in HTTPStatusError.
+const maxErrorBodySize = 256
+
+// HTTPStatusError is returned when the upstream API responds with a non-OK status code.
+// It unwraps to the matching sentinel error, if any.
+type HTTPStatusError struct {
+	Endpoint   Endpoint // Endpoint that produced the response
+	StatusCode int      // HTTP status code
+	Body       string   // Leading part of the response body
+	Err        error    // Matching sentinel error, nil for unclassified codes
+}
+
+// Error implements the error interface.
+func (e *HTTPStatusError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: status %d: %v", e.Endpoint, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%s: unexpected status code: %d", e.Endpoint, e.StatusCode)
+}
+
+// Unwrap returns the sentinel error matching the status code.
+func (e *HTTPStatusError) Unwrap() error {
+	return e.Err
+}
+
+// classifyStatus maps an upstream status code to a sentinel error.
+//
+// Parameters:
+//
+//	code - HTTP status code
+//
+// Returns:
+//
+//	The matching sentinel error, or nil if the code has no dedicated meaning
+func classifyStatus(code int) error {
+	switch {
+	case code == http.StatusBadRequest:
+		return ErrEmptyList
+	case code == http.StatusNotFound:
+		return ErrUserNotFound
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= http.StatusInternalServerError:
+		return ErrUpstreamUnavailable
+	default:
+		return nil
+	}
+}
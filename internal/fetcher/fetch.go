@@ -0,0 +1,134 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// endpointSpec declares everything the fetch engine needs to know about a list endpoint.
+type endpointSpec struct {
+	path string // Default path template relative to the base URL
+}
+
+// endpoints registers every list endpoint known to the fetcher.
+// Adding a new list type only requires an entry here and a typed Fetch* wrapper.
+var endpoints = map[Endpoint]endpointSpec{
+	EndpointFollows:  {path: "/api/getfollows/" + UsernamePlaceholder},
+	EndpointMods:     {path: "/api/getmods/" + UsernamePlaceholder},
+	EndpointVips:     {path: "/api/getvips/" + UsernamePlaceholder},
+	EndpointFounders: {path: "/api/getfounders/" + UsernamePlaceholder},
+}
+
+// RequestInfo describes a completed upstream request for instrumentation.
+type RequestInfo struct {
+	Endpoint   Endpoint      // Endpoint that was requested
+	Username   string        // Requested Twitch username
+	StatusCode int           // HTTP status code of the last attempt, zero if no response was received
+	Attempts   int           // Number of attempts performed
+	Items      int           // Number of decoded items
+	Duration   time.Duration // Total time spent on the request
+	Err        error         // Resulting error, nil on success
+}
+
+// Observer receives a RequestInfo after every upstream request.
+type Observer func(info RequestInfo)
+
+// fetch requests an endpoint and decodes the JSON list response into T,
+// retrying transient failures according to the endpoint's retry policy.
+//
+// Parameters:
+//
+//	ctx - Context bounding all attempts, including backoff delays
+//	f - Fetcher holding the client and endpoint configuration
+//	endpoint - Endpoint to request
+//	username - Twitch username to request data for
+//
+// Returns:
+//
+//	A slice of decoded items and an error if any
+func fetch[T any](ctx context.Context, f *Fetcher, endpoint Endpoint, username string) (items []T, err error) {
+	info := RequestInfo{Endpoint: endpoint, Username: username}
+	start := time.Now()
+
+	if f.observer != nil {
+		defer func() {
+			info.Items = len(items)
+			info.Duration = time.Since(start)
+			info.Err = err
+			f.observer(info)
+		}()
+	}
+
+	policy := f.retryPolicy(endpoint)
+
+	for attempt := 1; ; attempt++ {
+		var result attemptResult
+		items, result, err = fetchOnce[T](ctx, f, endpoint, username)
+		info.Attempts = attempt
+		info.StatusCode = result.statusCode
+
+		if err == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(result) {
+			return items, err
+		}
+
+		delay := max(policy.backoff(attempt), result.retryAfter)
+		if !wait(ctx, delay) {
+			return nil, err
+		}
+	}
+}
+
+// fetchOnce performs a single request attempt and decodes the response.
+//
+// Parameters:
+//
+//	ctx - Context for controlling request cancellation
+//	f - Fetcher holding the client and endpoint configuration
+//	endpoint - Endpoint to request
+//	username - Twitch username to request data for
+//
+// Returns:
+//
+//	A slice of decoded items, the attempt outcome used for retry decisions and an error if any
+func fetchOnce[T any](ctx context.Context, f *Fetcher, endpoint Endpoint, username string) ([]T, attemptResult, error) {
+	var result attemptResult
+
+	req, err := f.newRequest(ctx, endpoint, username)
+	if err != nil {
+		return nil, result, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, result, fmt.Errorf("failed to fetch %s: %w", endpoint, ctx.Err())
+		}
+		result.transient = true
+		return nil, result, fmt.Errorf("failed to fetch %s: %w: %v", endpoint, ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	result.statusCode = resp.StatusCode
+
+	if resp.StatusCode != http.StatusOK {
+		result.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, result, &HTTPStatusError{
+			Endpoint:   endpoint,
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			Err:        classifyStatus(resp.StatusCode),
+		}
+	}
+
+	var items []T
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, result, fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+	}
+
+	return items, result, nil
+}
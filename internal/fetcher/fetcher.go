@@ -2,12 +2,47 @@ package fetcher
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
+// Endpoint identifies an upstream API list endpoint.
+type Endpoint string
+
+const (
+	EndpointFollows  Endpoint = "follows"
+	EndpointMods     Endpoint = "mods"
+	EndpointVips     Endpoint = "vips"
+	EndpointFounders Endpoint = "founders"
+)
+
+const (
+	// DefaultBaseURL is the production upstream API.
+	DefaultBaseURL = "https://tools.2807.eu"
+
+	// DefaultUserAgent is sent when no custom user agent is configured.
+	DefaultUserAgent = "twitch-kit"
+
+	// UsernamePlaceholder is substituted with the requested username in endpoint path templates.
+	UsernamePlaceholder = "{username}"
+)
+
+// DefaultPaths returns the default path template for every known endpoint.
+//
+// Returns:
+//
+//	A new map of endpoint path templates
+func DefaultPaths() map[Endpoint]string {
+	paths := make(map[Endpoint]string, len(endpoints))
+	for endpoint, spec := range endpoints {
+		paths[endpoint] = spec.path
+	}
+	return paths
+}
+
 type Follow struct {
 	ID          string    `json:"id"`
 	DisplayName string    `json:"displayName"`
@@ -47,104 +82,131 @@ type Founders struct {
 
 // Fetcher handles HTTP requests to retrieve Twitch channel data.
 type Fetcher struct {
-	client *http.Client
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	paths     map[Endpoint]string
+	observer  Observer
+	retry     RetryPolicy
+	retries   map[Endpoint]RetryPolicy
 }
 
 // NewFetcher creates a new Fetcher instance with a configured HTTP client.
+// Without options it targets DefaultBaseURL with the default endpoint paths.
+//
+// Parameters:
+//
+//	opts - Optional settings such as base URL, HTTP client or endpoint paths
 //
 // Returns:
 //
 //	A pointer to a new Fetcher instance
-func NewFetcher() *Fetcher {
-	return &Fetcher{
-		client: &http.Client{Timeout: 10 * time.Second},
+func NewFetcher(opts ...Option) *Fetcher {
+	f := &Fetcher{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		baseURL:   DefaultBaseURL,
+		userAgent: DefaultUserAgent,
+		paths:     DefaultPaths(),
+		retry:     DefaultRetryPolicy(),
+		retries:   make(map[Endpoint]RetryPolicy),
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
-// FetchFollows retrieves the list of users followed by the specified Twitch user.
+// endpointURL builds the full request URL for an endpoint and username.
 //
 // Parameters:
 //
-//	ctx - Context for controlling request cancellation
-//	username - Twitch username to fetch follows for
+//	endpoint - Endpoint to request
+//	username - Twitch username substituted into the path template
 //
 // Returns:
 //
-//	A slice of Follow structs and an error if any
-func (f *Fetcher) FetchFollows(ctx context.Context, username string) ([]Follow, error) {
-	url := fmt.Sprintf("https://tools.2807.eu/api/getfollows/%s", username)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch follows: %v", err)
+//	The request URL and an error if the endpoint is not registered
+func (f *Fetcher) endpointURL(endpoint Endpoint, username string) (string, error) {
+	template, ok := f.paths[endpoint]
+	if !ok {
+		return "", fmt.Errorf("unknown endpoint: %s", endpoint)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusBadRequest {
-			return nil, fmt.Errorf("the user does not follow any channel")
-		}
-
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("user not found")
-		}
-
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	return f.baseURL + strings.ReplaceAll(template, UsernamePlaceholder, url.PathEscape(username)), nil
+}
 
-	var follows []Follow
-	if err := json.NewDecoder(resp.Body).Decode(&follows); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
+// retryPolicy returns the retry policy configured for an endpoint.
+//
+// Parameters:
+//
+//	endpoint - Endpoint to look up
+//
+// Returns:
+//
+//	The endpoint-specific policy, or the fetcher-wide default
+func (f *Fetcher) retryPolicy(endpoint Endpoint) RetryPolicy {
+	if policy, ok := f.retries[endpoint]; ok {
+		return policy
 	}
-
-	return follows, nil
+	return f.retry
 }
 
-// FetchMods retrieves the list of moderators for the specified Twitch channel.
+// newRequest creates a GET request for an endpoint with the configured headers.
 //
 // Parameters:
 //
 //	ctx - Context for controlling request cancellation
-//	username - Twitch username to fetch moderators for
+//	endpoint - Endpoint to request
+//	username - Twitch username to request data for
 //
 // Returns:
 //
-//	A slice of Mod structs and an error if any
-func (f *Fetcher) FetchMods(ctx context.Context, username string) ([]Mod, error) {
-	url := fmt.Sprintf("https://tools.2807.eu/api/getmods/%s", username)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	A prepared HTTP request and an error if any
+func (f *Fetcher) newRequest(ctx context.Context, endpoint Endpoint, username string) (*http.Request, error) {
+	url, err := f.endpointURL(endpoint, username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	resp, err := f.client.Do(req)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch mods: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusBadRequest {
-			return nil, fmt.Errorf("the user does not have any moderators on their channel")
-		}
 
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("user not found")
-		}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "application/json")
 
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	return req, nil
+}
 
-	var mods []Mod
-	if err := json.NewDecoder(resp.Body).Decode(&mods); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
+// FetchFollows retrieves the list of users followed by the specified Twitch user.
+//
+// Parameters:
+//
+//	ctx - Context for controlling request cancellation
+//	username - Twitch username to fetch follows for
+//
+// Returns:
+//
+//	A slice of Follow structs and an error if any
+func (f *Fetcher) FetchFollows(ctx context.Context, username string) ([]Follow, error) {
+	return fetch[Follow](ctx, f, EndpointFollows, username)
+}
 
-	return mods, nil
+// FetchMods retrieves the list of moderators for the specified Twitch channel.
+//
+// Parameters:
+//
+//	ctx - Context for controlling request cancellation
+//	username - Twitch username to fetch moderators for
+//
+// Returns:
+//
+//	A slice of Mod structs and an error if any
+func (f *Fetcher) FetchMods(ctx context.Context, username string) ([]Mod, error) {
+	return fetch[Mod](ctx, f, EndpointMods, username)
 }
 
 // FetchVips retrieves the list of VIPs for the specified Twitch channel.
@@ -158,36 +220,7 @@ func (f *Fetcher) FetchMods(ctx context.Context, username string) ([]Mod, error)
 //
 //	A slice of Vip structs and an error if any
 func (f *Fetcher) FetchVips(ctx context.Context, username string) ([]Vip, error) {
-	url := fmt.Sprintf("https://tools.2807.eu/api/getvips/%s", username)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch vips: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusBadRequest {
-			return nil, fmt.Errorf("the user does not have any VIPs on their channel")
-		}
-
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("user not found")
-		}
-
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	var vips []Vip
-	if err := json.NewDecoder(resp.Body).Decode(&vips); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	return vips, nil
+	return fetch[Vip](ctx, f, EndpointVips, username)
 }
 
 // FetchFounders retrieves the list of founders for the specified Twitch channel.
@@ -201,34 +234,5 @@ func (f *Fetcher) FetchVips(ctx context.Context, username string) ([]Vip, error)
 //
 //	A slice of Founders structs and an error if any
 func (f *Fetcher) FetchFounders(ctx context.Context, username string) ([]Founders, error) {
-	url := fmt.Sprintf("https://tools.2807.eu/api/getfounders/%s", username)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch founders: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusBadRequest {
-			return nil, fmt.Errorf("the user does not have any founders on their channel")
-		}
-
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("user not found")
-		}
-
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	var founders []Founders
-	if err := json.NewDecoder(resp.Body).Decode(&founders); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	return founders, nil
+	return fetch[Founders](ctx, f, EndpointFounders, username)
 }
@@ -0,0 +1,126 @@
+package fetcher
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures optional Fetcher settings.
+type Option func(*Fetcher)
+
+// WithBaseURL sets the upstream API base URL (scheme and host, optionally a path prefix).
+//
+// Parameters:
+//
+//	baseURL - Base URL such as "https://tools.2807.eu" or an httptest server URL
+//
+// Returns:
+//
+//	An Option that applies the base URL
+func WithBaseURL(baseURL string) Option {
+	return func(f *Fetcher) {
+		if baseURL != "" {
+			f.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client used for upstream requests.
+//
+// Parameters:
+//
+//	client - HTTP client to use; nil keeps the default
+//
+// Returns:
+//
+//	An Option that applies the client
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *Fetcher) {
+		if client != nil {
+			f.client = client
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every upstream request.
+//
+// Parameters:
+//
+//	userAgent - User-Agent header value
+//
+// Returns:
+//
+//	An Option that applies the user agent
+func WithUserAgent(userAgent string) Option {
+	return func(f *Fetcher) {
+		if userAgent != "" {
+			f.userAgent = userAgent
+		}
+	}
+}
+
+// WithEndpointPath overrides the path template of a single endpoint.
+// The template must contain the UsernamePlaceholder, e.g. "/api/getmods/{username}".
+//
+// Parameters:
+//
+//	endpoint - Endpoint to override
+//	template - Path template relative to the base URL
+//
+// Returns:
+//
+//	An Option that applies the path template
+func WithEndpointPath(endpoint Endpoint, template string) Option {
+	return func(f *Fetcher) {
+		if template != "" {
+			f.paths[endpoint] = template
+		}
+	}
+}
+
+// WithObserver registers a callback invoked after every upstream request,
+// e.g. to record latency metrics or log failures.
+//
+// Parameters:
+//
+//	observer - Callback receiving request details
+//
+// Returns:
+//
+//	An Option that applies the observer
+func WithObserver(observer Observer) Option {
+	return func(f *Fetcher) {
+		f.observer = observer
+	}
+}
+
+// WithRetryPolicy sets the retry policy used by endpoints without their own policy.
+//
+// Parameters:
+//
+//	policy - Retry policy to apply
+//
+// Returns:
+//
+//	An Option that applies the retry policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(f *Fetcher) {
+		f.retry = policy
+	}
+}
+
+// WithEndpointRetryPolicy sets the retry policy of a single endpoint.
+//
+// Parameters:
+//
+//	endpoint - Endpoint to configure
+//	policy - Retry policy to apply
+//
+// Returns:
+//
+//	An Option that applies the retry policy
+func WithEndpointRetryPolicy(endpoint Endpoint, policy RetryPolicy) Option {
+	return func(f *Fetcher) {
+		f.retries[endpoint] = policy
+	}
+}
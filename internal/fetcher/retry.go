@@ -0,0 +1,143 @@
+package fetcher
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient upstream failures are retried.
+type RetryPolicy struct {
+	MaxAttempts     int           // Total attempts including the first one; 1 disables retries
+	BaseDelay       time.Duration // Delay before the first retry, doubled on every further retry
+	MaxDelay        time.Duration // Upper bound for a single backoff delay
+	Jitter          float64       // Fraction (0..1) of each delay that is randomized
+	RetryableStatus []int         // Status codes that trigger a retry
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+//
+// Returns:
+//
+//	A RetryPolicy with three attempts and exponential backoff from 200ms to 2s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Jitter:      0.2,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// attemptResult carries the transport-level outcome of a single request attempt.
+type attemptResult struct {
+	statusCode int           // HTTP status code, zero if no response was received
+	retryAfter time.Duration // Delay requested by a Retry-After header, zero if absent
+	transient  bool          // Whether the failure happened at the transport level
+}
+
+// shouldRetry reports whether a failed attempt may be retried under the policy.
+//
+// Parameters:
+//
+//	result - Outcome of the failed attempt
+//
+// Returns:
+//
+//	True if the failure is transient according to the policy
+func (p RetryPolicy) shouldRetry(result attemptResult) bool {
+	if result.transient {
+		return true
+	}
+	return slices.Contains(p.RetryableStatus, result.statusCode)
+}
+
+// backoff computes the delay before the given retry.
+//
+// Parameters:
+//
+//	retry - One-based retry number
+//
+// Returns:
+//
+//	The jittered delay, capped at MaxDelay
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(float64(delay) * p.Jitter * rand.Float64())
+	}
+
+	return delay
+}
+
+// wait sleeps for the delay unless the context ends first or its deadline
+// would expire before the next attempt could start.
+//
+// Parameters:
+//
+//	ctx - Context bounding the whole fetch
+//	delay - Delay to wait
+//
+// Returns:
+//
+//	True if the caller may perform another attempt
+func wait(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+//
+// Parameters:
+//
+//	value - Header value
+//
+// Returns:
+//
+//	The requested delay, or zero if the header is absent or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
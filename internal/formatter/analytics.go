@@ -0,0 +1,153 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kirinyoku/twitch-kit/internal/analytics"
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// roleLabels maps roles to the labels used in analytics reports.
+var roleLabels = map[analytics.Role]string{
+	analytics.RoleMod: "mod",
+	analytics.RoleVip: "VIP",
+}
+
+// FormatOverlapMatrix creates a matrix of the numbers of mods and VIPs shared
+// by each pair of channels. The matrix is split on whole rows into parts of at
+// most limit bytes, each a complete preformatted block repeating the column
+// header, so every part is valid HTML on its own.
+//
+// Parameters:
+//
+//	overlap - Overlap of the compared channels
+//	limit - Maximum length of a part
+//
+// Returns:
+//
+//	The parts of the formatted matrix
+func FormatOverlapMatrix(overlap analytics.Overlap, limit int) []string {
+	const (
+		open  = "<pre>\n"
+		close = "</pre>"
+	)
+
+	width := 0
+	for _, channel := range overlap.Channels {
+		width = max(width, len(channel))
+	}
+
+	header := fmt.Sprintf("%-*s", width+3, "#")
+	for i := range overlap.Channels {
+		header += fmt.Sprintf("%5d", i+1)
+	}
+	header += "\n"
+
+	var parts []string
+	part := fmt.Sprintf("Shared mods and VIPs across %d channels:\n", len(overlap.Channels)) + open + header
+	rows := 0
+
+	for i, channel := range overlap.Channels {
+		row := fmt.Sprintf("%-2d %-*s", i+1, width, channel)
+		for _, count := range overlap.Matrix[i] {
+			row += fmt.Sprintf("%5d", count)
+		}
+		row += "\n"
+
+		if rows > 0 && len(part)+len(row)+len(close) > limit {
+			parts = append(parts, part+close)
+			part, rows = open+header, 0
+		}
+		part += row
+		rows++
+	}
+
+	return append(parts, part+close)
+}
+
+// FormatOverlap creates a report of users who are mods or VIPs in several channels.
+//
+// Parameters:
+//
+//	overlap - Overlap of the compared channels
+//
+// Returns:
+//
+//	A formatted string with HTML links to shared users
+func FormatOverlap(overlap analytics.Overlap) string {
+	if len(overlap.Users) == 0 {
+		return "No users are shared between these channels.\n"
+	}
+
+	response := fmt.Sprintf("Users in more than one channel (%d):\n", len(overlap.Users))
+	for i, user := range overlap.Users {
+		channels := make(map[analytics.Role][]string)
+		var roles []analytics.Role
+		for _, m := range user.Memberships {
+			if _, ok := channels[m.Role]; !ok {
+				roles = append(roles, m.Role)
+			}
+			channels[m.Role] = append(channels[m.Role], m.Channel)
+		}
+
+		var parts []string
+		for _, role := range roles {
+			parts = append(parts, fmt.Sprintf("%s in %s", roleLabels[role], strings.Join(channels[role], ", ")))
+		}
+
+		response += fmt.Sprintf("%d. <a href=\"https://twitch.tv/%s\">%s</a>: %s\n", i+1, user.Login, user.DisplayName, strings.Join(parts, "; "))
+	}
+
+	return response
+}
+
+// FormatFollowComparison creates a report of the channels two users follow.
+// It lists the similarity score, the earliest common follow and the channels
+// followed by both users or by only one of them.
+//
+// Parameters:
+//
+//	comparison - Comparison of the two follow lists
+//
+// Returns:
+//
+//	A formatted string with HTML links to the compared channels
+func FormatFollowComparison(comparison analytics.FollowComparison) string {
+	first := fmt.Sprintf("<a href=\"https://twitch.tv/%s\">%s</a>", comparison.First, comparison.First)
+	second := fmt.Sprintf("<a href=\"https://twitch.tv/%s\">%s</a>", comparison.Second, comparison.Second)
+
+	response := fmt.Sprintf("%s and %s follow %d channels in common (similarity %.1f%%).\n",
+		first, second, len(comparison.Common), comparison.Similarity*100)
+
+	if earliest, ok := comparison.EarliestCommon(); ok {
+		response += fmt.Sprintf("Earliest common follow: <a href=\"https://twitch.tv/%s\">%s</a> (both since %s)\n",
+			earliest.Login, earliest.DisplayName, earliest.Since().Format("2006-01-02"))
+	}
+
+	if len(comparison.Common) > 0 {
+		response += fmt.Sprintf("\nFollowed by both (%d):\n", len(comparison.Common))
+		for i, common := range comparison.Common {
+			response += fmt.Sprintf("%d. <a href=\"https://twitch.tv/%s\">%s</a> (%s / %s)\n", i+1, common.Login, common.DisplayName,
+				common.FirstFollowedAt.Format("2006-01-02"), common.SecondFollowedAt.Format("2006-01-02"))
+		}
+	}
+
+	for _, only := range []struct {
+		login   string
+		follows []fetcher.Follow
+	}{
+		{comparison.First, comparison.OnlyFirst},
+		{comparison.Second, comparison.OnlySecond},
+	} {
+		if len(only.follows) == 0 {
+			continue
+		}
+		response += fmt.Sprintf("\nFollowed only by %s (%d):\n", only.login, len(only.follows))
+		for i, follow := range only.follows {
+			response += fmt.Sprintf("%d. <a href=\"https://twitch.tv/%s\">%s</a> (followed at %s)\n", i+1, follow.Login, follow.DisplayName, follow.FollowedAt.Format("2006-01-02"))
+		}
+	}
+
+	return response
+}
@@ -2,7 +2,9 @@ package formatter
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/kirinyoku/twitch-kit/internal/diff"
 	"github.com/kirinyoku/twitch-kit/internal/fetcher"
 )
 
@@ -90,3 +92,104 @@ func FormatFounders(username string, founders []fetcher.Founders) string {
 	}
 	return response
 }
+
+// listLabels maps list types to the plural nouns used in formatted output.
+var listLabels = map[fetcher.Endpoint]string{
+	fetcher.EndpointFollows:  "follows",
+	fetcher.EndpointMods:     "moders",
+	fetcher.EndpointVips:     "vips",
+	fetcher.EndpointFounders: "founders",
+}
+
+// FormatDiff creates a formatted string describing how a channel list changed over time.
+// It lists added, removed and changed users as HTML links.
+//
+// Parameters:
+//
+//	result - Diff result to format
+//
+// Returns:
+//
+//	A formatted string with the added, removed and changed users
+func FormatDiff(result diff.Result) string {
+	from := result.From.Format("2006-01-02 15:04")
+	to := result.To.Format("2006-01-02 15:04")
+	channel := fmt.Sprintf("<a href=\"https://twitch.tv/%s\">%s</a>", result.Channel, result.Channel)
+
+	note := ""
+	if !result.Since.IsZero() && result.Since.Before(result.From) {
+		note = fmt.Sprintf("History of %s's list of %s only goes back to %s, so the changes since %s are not all known.\n",
+			channel, listLabels[result.List], from, result.Since.Format("2006-01-02 15:04"))
+	}
+
+	if result.Empty() {
+		return note + fmt.Sprintf("No changes in %s's list of %s between %s and %s.\n", channel, listLabels[result.List], from, to)
+	}
+
+	response := note + fmt.Sprintf("Changes in %s's list of %s between %s and %s:\n", channel, listLabels[result.List], from, to)
+
+	if len(result.Added) > 0 {
+		response += fmt.Sprintf("\nAdded (%d):\n", len(result.Added))
+		for i, entry := range result.Added {
+			response += fmt.Sprintf("%d. %s\n", i+1, formatEntry(entry))
+		}
+	}
+
+	if len(result.Removed) > 0 {
+		response += fmt.Sprintf("\nRemoved (%d):\n", len(result.Removed))
+		for i, entry := range result.Removed {
+			response += fmt.Sprintf("%d. %s\n", i+1, formatEntry(entry))
+		}
+	}
+
+	if len(result.Changed) > 0 {
+		response += fmt.Sprintf("\nChanged (%d):\n", len(result.Changed))
+		for i, changed := range result.Changed {
+			var changes []string
+			for _, change := range changed.Changes {
+				changes = append(changes, fmt.Sprintf("%s: %t → %t", change.Field, change.Old, change.New))
+			}
+			response += fmt.Sprintf("%d. %s (%s)\n", i+1, formatEntry(changed.Entry), strings.Join(changes, ", "))
+		}
+	}
+
+	return response
+}
+
+// formatEntry renders a diff entry as an HTML link to the user's channel.
+func formatEntry(entry diff.Entry) string {
+	return fmt.Sprintf("<a href=\"https://twitch.tv/%s\">%s</a>", entry.Login, entry.DisplayName)
+}
+
+// FormatWatchNotification creates a notification about users added to or removed from a watched list.
+//
+// Parameters:
+//
+//	result - Diff result between the previous and current poll
+//
+// Returns:
+//
+//	A formatted string with the added and removed users
+func FormatWatchNotification(result diff.Result) string {
+	response := fmt.Sprintf("🔔 <a href=\"https://twitch.tv/%s\">%s</a>'s list of %s changed:\n", result.Channel, result.Channel, listLabels[result.List])
+	for _, entry := range result.Added {
+		response += fmt.Sprintf("+ %s\n", formatEntry(entry))
+	}
+	for _, entry := range result.Removed {
+		response += fmt.Sprintf("− %s\n", formatEntry(entry))
+	}
+	return response
+}
+
+// FormatLiveNotification creates a notification about a followed channel going live.
+//
+// Parameters:
+//
+//	follow - Followed channel that went live
+//
+// Returns:
+//
+//	A formatted string with an HTML link to the stream
+func FormatLiveNotification(follow fetcher.Follow) string {
+	return fmt.Sprintf("🔴 <a href=\"https://twitch.tv/%s\">%s</a> went live!\n", follow.Login, follow.DisplayName)
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortCodes maps sort orders to their compact codes; upper case means descending.
+var sortCodes = map[Sort]string{
+	SortDate: "d",
+	SortName: "n",
+	SortLive: "l",
+}
+
+// Encode renders the query in a compact form that fits into callback data,
+// e.g. "D.b.fs6k0w0" for newest first, banned only, since a base-36 Unix time.
+//
+// Returns:
+//
+//	The encoded query, empty for the zero query
+func (q Query) Encode() string {
+	var parts []string
+
+	if code, ok := sortCodes[q.Sort]; ok {
+		if q.Desc {
+			code = strings.ToUpper(code)
+		}
+		parts = append(parts, code)
+	}
+	if q.Banned {
+		parts = append(parts, "b")
+	}
+	if q.Live {
+		parts = append(parts, "v")
+	}
+	if q.Subscribed {
+		parts = append(parts, "s")
+	}
+	if !q.From.IsZero() {
+		parts = append(parts, "f"+strconv.FormatInt(q.From.Unix(), 36))
+	}
+	if !q.To.IsZero() {
+		parts = append(parts, "t"+strconv.FormatInt(q.To.Unix(), 36))
+	}
+	// The substring may contain dots, so it always comes last.
+	if q.Contains != "" {
+		parts = append(parts, "c"+q.Contains)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Decode parses a query produced by Encode.
+//
+// Parameters:
+//
+//	text - Encoded query
+//
+// Returns:
+//
+//	The decoded query and an error if the text is malformed
+func Decode(text string) (Query, error) {
+	var q Query
+
+	for text != "" {
+		if contains, ok := strings.CutPrefix(text, "c"); ok {
+			q.Contains = contains
+			break
+		}
+
+		var part string
+		part, text, _ = strings.Cut(text, ".")
+
+		switch {
+		case part == "b":
+			q.Banned = true
+		case part == "v":
+			q.Live = true
+		case part == "s":
+			q.Subscribed = true
+		case len(part) > 1 && (part[0] == 'f' || part[0] == 't'):
+			seconds, err := strconv.ParseInt(part[1:], 36, 64)
+			if err != nil {
+				return Query{}, fmt.Errorf("invalid query %q: %w", part, err)
+			}
+			date := time.Unix(seconds, 0).UTC()
+			if part[0] == 'f' {
+				q.From = date
+			} else {
+				q.To = date
+			}
+		default:
+			if !q.setSortCode(part) {
+				return Query{}, fmt.Errorf("invalid query %q", part)
+			}
+		}
+	}
+
+	return q, nil
+}
+
+// setSortCode applies a compact sort code and reports whether it is known.
+func (q *Query) setSortCode(code string) bool {
+	for sort, c := range sortCodes {
+		switch code {
+		case c:
+			q.Sort, q.Desc = sort, false
+			return true
+		case strings.ToUpper(c):
+			q.Sort, q.Desc = sort, true
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		q    Query
+		want string
+	}{
+		{name: "zero query", want: ""},
+		{name: "sort", q: Query{Sort: SortDate}, want: "d"},
+		{name: "reversed sort", q: Query{Sort: SortName, Desc: true}, want: "N"},
+		{name: "live sort", q: Query{Sort: SortLive}, want: "l"},
+		{name: "filters", q: Query{Banned: true, Live: true, Subscribed: true}, want: "b.v.s"},
+		{name: "dates", q: Query{From: day(1, 2), To: day(3, 5)}, want: "f" + base36(day(1, 2)) + ".t" + base36(day(3, 5))},
+		{name: "substring with dots and colons", q: Query{Sort: SortDate, Contains: "a.b:c"}, want: "d.ca.b:c"},
+		{name: "substring starting like a flag", q: Query{Contains: "b.v"}, want: "cb.v"},
+		{
+			name: "every option",
+			q:    Query{Sort: SortDate, Desc: true, Banned: true, Subscribed: true, From: day(1, 1), To: day(6, 1), Contains: "x"},
+			want: "D.b.s.f" + base36(day(1, 1)) + ".t" + base36(day(6, 1)) + ".cx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.q.Encode()
+			if encoded != tt.want {
+				t.Errorf("Encode() = %q, want %q", encoded, tt.want)
+			}
+
+			decoded, err := Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode(%q): %v", encoded, err)
+			}
+			if decoded != tt.q {
+				t.Errorf("Decode(%q) = %+v, want %+v", encoded, decoded, tt.q)
+			}
+		})
+	}
+}
+
+func TestParsedQueriesSurviveEncoding(t *testing.T) {
+	inputs := []string{
+		"--sort=-date --banned --from=2024-01-01 --to=2024-02-01",
+		"--desc --sort=name --contains=a.b",
+		"--sort=live --live --since=30d",
+		"--sort=date --asc --subscribed",
+	}
+
+	for _, input := range inputs {
+		_, q, err := Parse(input, testNow)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+
+		decoded, err := Decode(q.Encode())
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", q.Encode(), err)
+		}
+		// Encoding keeps whole seconds, which --since may not be.
+		q.From = q.From.Truncate(time.Second)
+		if decoded != q {
+			t.Errorf("%q: decoded %+v, want %+v", input, decoded, q)
+		}
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	for _, text := range []string{"x", "d..b", "f", "fzz!", "t-", "D.q"} {
+		if _, err := Decode(text); err == nil {
+			t.Errorf("Decode(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+// TestEncodeFitsCallbackData checks that the longest query the keyboard can
+// produce for a list, with a 25-character login and the longest button and
+// callback prefix, fits into Telegram's 64-byte callback data.
+func TestEncodeFitsCallbackData(t *testing.T) {
+	const (
+		callbackDataLimit = 64
+		longestPrefix     = "retry:founders:" + "abcdefghijklmnopqrstuvwxy" + ":"
+	)
+
+	q := Query{Sort: SortDate, Desc: true, Banned: true, Subscribed: true}
+	if data := longestPrefix + q.Encode(); len(data) > callbackDataLimit {
+		t.Errorf("callback data %q is %d bytes, want at most %d", data, len(data), callbackDataLimit)
+	}
+}
+
+// base36 returns the encoded Unix time of a date.
+func base36(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 36)
+}
@@ -0,0 +1,345 @@
+// Package query sorts and filters Twitch channel lists before they are formatted.
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+)
+
+// Sort is the order of a list.
+type Sort string
+
+const (
+	SortNone Sort = ""     // Upstream order
+	SortDate Sort = "date" // By follow or grant date, oldest first
+	SortName Sort = "name" // By display name
+	SortLive Sort = "live" // Live channels first, follows only
+)
+
+// dateLayout is the date format accepted by the --from and --to flags.
+const dateLayout = "2006-01-02"
+
+var (
+	ErrUnknownFlag  = errors.New("unknown option")
+	ErrInvalidValue = errors.New("invalid option value")
+	ErrUnsupported  = errors.New("option is not available for this list")
+)
+
+// FlagError reports a problem with a single query flag.
+type FlagError struct {
+	Flag string // Flag as typed by the user, e.g. "--sort=size"
+	Err  error  // ErrUnknownFlag, ErrInvalidValue or ErrUnsupported
+}
+
+// Error implements the error interface.
+func (e *FlagError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Flag, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FlagError) Unwrap() error {
+	return e.Err
+}
+
+// Query describes how a list is sorted and filtered.
+type Query struct {
+	Sort       Sort      // Sort order, SortNone keeps the upstream order
+	Desc       bool      // Whether the sort order is reversed
+	Banned     bool      // Keep only banned users
+	Live       bool      // Keep only live channels
+	Subscribed bool      // Keep only subscribed founders
+	From       time.Time // Keep entries dated at or after From, zero for no bound
+	To         time.Time // Keep entries dated before To, zero for no bound
+	Contains   string    // Keep entries whose login or display name contains this text
+}
+
+// Item holds the attributes of a list entry a query can use.
+type Item struct {
+	Login       string
+	DisplayName string
+	Date        time.Time // Follow, grant or first subscription date
+	Live        bool
+	Banned      bool
+	Subscribed  bool
+}
+
+// IsZero reports whether the query keeps the list unchanged.
+//
+// Returns:
+//
+//	True if no sorting or filtering is requested
+func (q Query) IsZero() bool {
+	return q == Query{}
+}
+
+// Validate checks that every option of the query applies to a list type.
+//
+// Parameters:
+//
+//	list - List type the query is applied to
+//
+// Returns:
+//
+//	A *FlagError wrapping ErrUnsupported for the first inapplicable option
+func (q Query) Validate(list fetcher.Endpoint) error {
+	switch {
+	case (q.Live || q.Sort == SortLive) && list != fetcher.EndpointFollows:
+		return &FlagError{Flag: "--live", Err: ErrUnsupported}
+	case q.Banned && list == fetcher.EndpointFollows:
+		return &FlagError{Flag: "--banned", Err: ErrUnsupported}
+	case q.Subscribed && list != fetcher.EndpointFounders:
+		return &FlagError{Flag: "--subscribed", Err: ErrUnsupported}
+	}
+	return nil
+}
+
+// Parse extracts query flags from command arguments, e.g.
+// "xqc --sort=-date --banned --from=2024-01-01 --contains=bot".
+//
+// Supported flags:
+//
+//	--sort=date|name|live (prefix the value with "-" to reverse it)
+//	--asc, --desc (only together with --sort)
+//	--banned, --live, --subscribed
+//	--from=YYYY-MM-DD, --to=YYYY-MM-DD, --since=<period>
+//	--contains=<text>
+//
+// Parameters:
+//
+//	text - Command arguments
+//	now - Current time used by --since
+//
+// Returns:
+//
+//	The arguments without flags, the parsed query and a *FlagError wrapping
+//	ErrUnknownFlag or ErrInvalidValue for the first offending flag
+func Parse(text string, now time.Time) (string, Query, error) {
+	var (
+		q     Query
+		rest  []string
+		order string // Last --asc or --desc flag as typed
+	)
+
+	for _, field := range strings.Fields(text) {
+		flag, ok := strings.CutPrefix(field, "--")
+		if !ok {
+			rest = append(rest, field)
+			continue
+		}
+
+		name, value, _ := strings.Cut(flag, "=")
+		name = strings.ToLower(name)
+		if err := q.set(name, value, now); err != nil {
+			return "", Query{}, &FlagError{Flag: field, Err: err}
+		}
+		if name == "asc" || name == "desc" {
+			order = field
+		}
+	}
+
+	// A direction without an order would change nothing and be lost on encoding.
+	if order != "" && q.Sort == SortNone {
+		return "", Query{}, &FlagError{Flag: order, Err: ErrInvalidValue}
+	}
+
+	return strings.Join(rest, " "), q, nil
+}
+
+// set applies a single flag to the query.
+func (q *Query) set(name, value string, now time.Time) error {
+	switch name {
+	case "sort":
+		desc := strings.HasPrefix(value, "-")
+		switch sort := Sort(strings.ToLower(strings.TrimPrefix(value, "-"))); sort {
+		case SortDate, SortName, SortLive:
+			// A preceding --desc still applies, e.g. "--desc --sort=date".
+			q.Sort, q.Desc = sort, q.Desc || desc
+		default:
+			return ErrInvalidValue
+		}
+
+	case "asc", "desc":
+		q.Desc = name == "desc"
+
+	case "banned":
+		q.Banned = true
+
+	case "live":
+		q.Live = true
+
+	case "subscribed":
+		q.Subscribed = true
+
+	case "from", "to":
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return ErrInvalidValue
+		}
+		if name == "from" {
+			q.From = date
+		} else {
+			q.To = date.AddDate(0, 0, 1)
+		}
+
+	case "since":
+		period, err := utils.ParsePeriod(value)
+		if err != nil {
+			return ErrInvalidValue
+		}
+		q.From = now.Add(-period)
+
+	case "contains", "search":
+		if value == "" {
+			return ErrInvalidValue
+		}
+		q.Contains = value
+
+	default:
+		return ErrUnknownFlag
+	}
+
+	return nil
+}
+
+// Apply filters and sorts items according to the query. The input slice is
+// never modified, so it may be shared, e.g. with a cache.
+//
+// Parameters:
+//
+//	items - List entries in upstream order
+//	q - Query to apply
+//	describe - Function returning the attributes of an entry
+//
+// Returns:
+//
+//	A new slice with the matching entries in query order
+func Apply[T any](items []T, q Query, describe func(T) Item) []T {
+	contains := strings.ToLower(q.Contains)
+
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		it := describe(item)
+
+		switch {
+		case q.Banned && !it.Banned,
+			q.Live && !it.Live,
+			q.Subscribed && !it.Subscribed,
+			!q.From.IsZero() && it.Date.Before(q.From),
+			!q.To.IsZero() && !it.Date.Before(q.To),
+			contains != "" && !strings.Contains(strings.ToLower(it.Login), contains) &&
+				!strings.Contains(strings.ToLower(it.DisplayName), contains):
+			continue
+		}
+
+		result = append(result, item)
+	}
+
+	if q.Sort == SortNone {
+		return result
+	}
+
+	slices.SortStableFunc(result, func(a, b T) int {
+		x, y := describe(a), describe(b)
+
+		var c int
+		switch q.Sort {
+		case SortDate:
+			c = x.Date.Compare(y.Date)
+		case SortName:
+			c = cmp.Compare(strings.ToLower(x.DisplayName), strings.ToLower(y.DisplayName))
+		case SortLive:
+			// Live channels come first; ties keep the upstream order.
+			c = -compareBool(x.Live, y.Live)
+		}
+
+		if q.Desc {
+			return -c
+		}
+		return c
+	})
+
+	return result
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Follows applies the query to a follow list.
+//
+// Parameters:
+//
+//	follows - Follow list
+//	q - Query to apply
+//
+// Returns:
+//
+//	The matching follows in query order
+func Follows(follows []fetcher.Follow, q Query) []fetcher.Follow {
+	return Apply(follows, q, func(f fetcher.Follow) Item {
+		return Item{Login: f.Login, DisplayName: f.DisplayName, Date: f.FollowedAt, Live: f.IsLive}
+	})
+}
+
+// Mods applies the query to a moderator list.
+//
+// Parameters:
+//
+//	mods - Moderator list
+//	q - Query to apply
+//
+// Returns:
+//
+//	The matching moderators in query order
+func Mods(mods []fetcher.Mod, q Query) []fetcher.Mod {
+	return Apply(mods, q, func(m fetcher.Mod) Item {
+		return Item{Login: m.Login, DisplayName: m.DisplayName, Date: m.GrantedAt, Banned: m.Banned}
+	})
+}
+
+// Vips applies the query to a VIP list.
+//
+// Parameters:
+//
+//	vips - VIP list
+//	q - Query to apply
+//
+// Returns:
+//
+//	The matching VIPs in query order
+func Vips(vips []fetcher.Vip, q Query) []fetcher.Vip {
+	return Apply(vips, q, func(v fetcher.Vip) Item {
+		return Item{Login: v.Login, DisplayName: v.DisplayName, Date: v.GrantedAt, Banned: v.Banned}
+	})
+}
+
+// Founders applies the query to a founder list.
+//
+// Parameters:
+//
+//	founders - Founder list
+//	q - Query to apply
+//
+// Returns:
+//
+//	The matching founders in query order
+func Founders(founders []fetcher.Founders, q Query) []fetcher.Founders {
+	return Apply(founders, q, func(f fetcher.Founders) Item {
+		return Item{Login: f.Login, DisplayName: f.DisplayName, Date: f.FirstMonth, Subscribed: f.IsSubscribed, Banned: f.Banned}
+	})
+}
@@ -0,0 +1,159 @@
+package query
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// testNow is the reference time of parsed --since flags.
+var testNow = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantRest string
+		want     Query
+		wantErr  error
+		wantFlag string // Flag reported by the error
+	}{
+		{name: "no flags", text: "xqc shroud", wantRest: "xqc shroud"},
+		{name: "sort", text: "xqc --sort=date", wantRest: "xqc", want: Query{Sort: SortDate}},
+		{name: "reversed sort", text: "--sort=-name xqc", wantRest: "xqc", want: Query{Sort: SortName, Desc: true}},
+		{name: "flag names ignore case", text: "--SORT=Live", want: Query{Sort: SortLive}},
+		{name: "desc after sort", text: "--sort=date --desc", want: Query{Sort: SortDate, Desc: true}},
+		{name: "desc before sort", text: "--desc --sort=date", want: Query{Sort: SortDate, Desc: true}},
+		{name: "asc overrides a reversed sort", text: "--sort=-date --asc", want: Query{Sort: SortDate}},
+		{name: "filters", text: "--banned --live --subscribed", want: Query{Banned: true, Live: true, Subscribed: true}},
+		{name: "dates", text: "--from=2024-01-02 --to=2024-03-04", want: Query{From: day(1, 2), To: day(3, 5)}},
+		{name: "since", text: "--since=7d", want: Query{From: testNow.AddDate(0, 0, -7)}},
+		{name: "contains", text: "--contains=Bot", want: Query{Contains: "Bot"}},
+		{name: "search alias", text: "--search=bot", want: Query{Contains: "bot"}},
+
+		{name: "unknown flag", text: "xqc --size", wantErr: ErrUnknownFlag, wantFlag: "--size"},
+		{name: "unknown sort", text: "--sort=size", wantErr: ErrInvalidValue, wantFlag: "--sort=size"},
+		{name: "invalid date", text: "--from=yesterday", wantErr: ErrInvalidValue, wantFlag: "--from=yesterday"},
+		{name: "invalid period", text: "--since=soon", wantErr: ErrInvalidValue, wantFlag: "--since=soon"},
+		{name: "empty substring", text: "--contains=", wantErr: ErrInvalidValue, wantFlag: "--contains="},
+		{name: "desc without sort", text: "xqc --desc", wantErr: ErrInvalidValue, wantFlag: "--desc"},
+		{name: "asc without sort", text: "--ASC", wantErr: ErrInvalidValue, wantFlag: "--ASC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, q, err := Parse(tt.text, testNow)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if err != nil {
+				var flagErr *FlagError
+				if !errors.As(err, &flagErr) || flagErr.Flag != tt.wantFlag {
+					t.Errorf("Parse(%q) error = %v, want flag %q", tt.text, err, tt.wantFlag)
+				}
+				return
+			}
+
+			if rest != tt.wantRest {
+				t.Errorf("Parse(%q) rest = %q, want %q", tt.text, rest, tt.wantRest)
+			}
+			if q != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.text, q, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        Query
+		list     fetcher.Endpoint
+		wantFlag string // Flag reported as unsupported, empty if valid
+	}{
+		{name: "zero query", list: fetcher.EndpointMods},
+		{name: "live filter of follows", q: Query{Live: true}, list: fetcher.EndpointFollows},
+		{name: "live sort of follows", q: Query{Sort: SortLive}, list: fetcher.EndpointFollows},
+		{name: "live filter of mods", q: Query{Live: true}, list: fetcher.EndpointMods, wantFlag: "--live"},
+		{name: "live sort of VIPs", q: Query{Sort: SortLive}, list: fetcher.EndpointVips, wantFlag: "--live"},
+		{name: "banned filter of VIPs", q: Query{Banned: true}, list: fetcher.EndpointVips},
+		{name: "banned filter of follows", q: Query{Banned: true}, list: fetcher.EndpointFollows, wantFlag: "--banned"},
+		{name: "subscribed filter of founders", q: Query{Subscribed: true}, list: fetcher.EndpointFounders},
+		{name: "subscribed filter of mods", q: Query{Subscribed: true}, list: fetcher.EndpointMods, wantFlag: "--subscribed"},
+		{name: "dates apply to every list", q: Query{From: day(1, 1), To: day(2, 1), Sort: SortDate}, list: fetcher.EndpointFounders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.Validate(tt.list)
+
+			if tt.wantFlag == "" {
+				if err != nil {
+					t.Errorf("Validate(%s) = %v, want nil", tt.list, err)
+				}
+				return
+			}
+
+			var flagErr *FlagError
+			if !errors.As(err, &flagErr) || flagErr.Flag != tt.wantFlag || !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Validate(%s) = %v, want %s unsupported", tt.list, err, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	items := []Item{
+		{Login: "carol", DisplayName: "Carol", Date: day(3, 1), Live: true},
+		{Login: "alice", DisplayName: "alice", Date: day(1, 1), Banned: true},
+		{Login: "bob_bot", DisplayName: "Bob", Date: day(2, 1), Live: true, Banned: true, Subscribed: true},
+		{Login: "dave", DisplayName: "Dave", Date: day(2, 1)},
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string // Logins in result order
+	}{
+		{name: "zero query keeps the upstream order", want: []string{"carol", "alice", "bob_bot", "dave"}},
+		{name: "oldest first, ties keep the upstream order", q: Query{Sort: SortDate}, want: []string{"alice", "bob_bot", "dave", "carol"}},
+		{name: "newest first", q: Query{Sort: SortDate, Desc: true}, want: []string{"carol", "bob_bot", "dave", "alice"}},
+		{name: "by name ignoring case", q: Query{Sort: SortName}, want: []string{"alice", "bob_bot", "carol", "dave"}},
+		{name: "live first", q: Query{Sort: SortLive}, want: []string{"carol", "bob_bot", "alice", "dave"}},
+		{name: "banned only", q: Query{Banned: true}, want: []string{"alice", "bob_bot"}},
+		{name: "live only", q: Query{Live: true}, want: []string{"carol", "bob_bot"}},
+		{name: "subscribed only", q: Query{Subscribed: true}, want: []string{"bob_bot"}},
+		{name: "from is inclusive", q: Query{From: day(2, 1)}, want: []string{"carol", "bob_bot", "dave"}},
+		{name: "to is exclusive", q: Query{To: day(2, 1)}, want: []string{"alice"}},
+		{name: "contains matches the login", q: Query{Contains: "BOT"}, want: []string{"bob_bot"}},
+		{name: "contains matches the display name", q: Query{Contains: "dav"}, want: []string{"dave"}},
+		{name: "filters combine", q: Query{Banned: true, Live: true, Sort: SortName, Desc: true}, want: []string{"bob_bot"}},
+		{name: "nothing matches", q: Query{Contains: "zzz"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(items)
+			result := Apply(input, tt.q, func(it Item) Item { return it })
+
+			got := make([]string, 0, len(result))
+			for _, it := range result {
+				got = append(got, it.Login)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Apply(%+v) = %v, want %v", tt.q, got, tt.want)
+			}
+			if !slices.Equal(input, items) {
+				t.Error("Apply modified its input")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// accessBucket is the top-level bucket holding access rules keyed by Telegram user ID.
+const accessBucket = "access"
+
+// SetAccessRule stores a user's access rule, replacing any existing one.
+//
+// Parameters:
+//
+//	userID - Telegram user ID
+//	rule - Name of the rule to store
+//
+// Returns:
+//
+//	An error if the rule cannot be stored
+func (s *Store) SetAccessRule(userID int64, rule string) error {
+	const op = "storage.SetAccessRule"
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(accessBucket))
+		if err != nil {
+			return err
+		}
+		return root.Put(chatKey(userID), []byte(rule))
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteAccessRule removes a user's access rule.
+//
+// Parameters:
+//
+//	userID - Telegram user ID
+//
+// Returns:
+//
+//	Whether a rule existed and an error if any
+func (s *Store) DeleteAccessRule(userID int64) (bool, error) {
+	const op = "storage.DeleteAccessRule"
+
+	removed := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(accessBucket))
+		if root == nil || root.Get(chatKey(userID)) == nil {
+			return nil
+		}
+
+		removed = true
+		return root.Delete(chatKey(userID))
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return removed, nil
+}
+
+// AccessRule returns a user's access rule.
+//
+// Parameters:
+//
+//	userID - Telegram user ID
+//
+// Returns:
+//
+//	The name of the rule, whether one exists and an error if any
+func (s *Store) AccessRule(userID int64) (string, bool, error) {
+	const op = "storage.AccessRule"
+
+	var rule string
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(accessBucket))
+		if root == nil {
+			return nil
+		}
+
+		if v := root.Get(chatKey(userID)); v != nil {
+			rule = string(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rule, rule != "", nil
+}
+
+// AccessRules returns every stored access rule.
+//
+// Returns:
+//
+//	The names of the rules keyed by Telegram user ID and an error if any
+func (s *Store) AccessRules() (map[int64]string, error) {
+	const op = "storage.AccessRules"
+
+	rules := make(map[int64]string)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(accessBucket))
+		if root == nil {
+			return nil
+		}
+
+		return root.ForEach(func(k, v []byte) error {
+			userID, err := strconv.ParseInt(string(k), 10, 64)
+			if err != nil {
+				return err
+			}
+			rules[userID] = string(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
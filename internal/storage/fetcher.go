@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+)
+
+// Fetcher defines the interface for fetching Twitch channel data that the recorder wraps.
+type Fetcher interface {
+	FetchFollows(ctx context.Context, username string) ([]fetcher.Follow, error)
+	FetchMods(ctx context.Context, username string) ([]fetcher.Mod, error)
+	FetchVips(ctx context.Context, username string) ([]fetcher.Vip, error)
+	FetchFounders(ctx context.Context, username string) ([]fetcher.Founders, error)
+}
+
+// Recorder is a Fetcher decorator that saves every successful result as a
+// snapshot and serves sufficiently recent snapshots without an upstream call,
+// so the cache stays warm across restarts.
+type Recorder struct {
+	next     Fetcher       // Wrapped fetcher
+	store    *Store        // Snapshot store
+	freshFor time.Duration // Maximum snapshot age served without fetching, zero to always fetch
+}
+
+// NewRecorder creates a new Recorder wrapping the provided fetcher.
+//
+// Parameters:
+//
+//	next - Fetcher to delegate to
+//	store - Snapshot store to save results in
+//	freshFor - Maximum snapshot age served without fetching, zero to always fetch
+//
+// Returns:
+//
+//	A pointer to a new Recorder instance
+func NewRecorder(next Fetcher, store *Store, freshFor time.Duration) *Recorder {
+	return &Recorder{
+		next:     next,
+		store:    store,
+		freshFor: freshFor,
+	}
+}
+
+// FetchFollows returns a recent follows snapshot or fetches and records the follows of a user.
+func (r *Recorder) FetchFollows(ctx context.Context, username string) ([]fetcher.Follow, error) {
+	return record(ctx, r, fetcher.EndpointFollows, username, r.next.FetchFollows)
+}
+
+// FetchMods returns a recent moderators snapshot or fetches and records the moderators of a channel.
+func (r *Recorder) FetchMods(ctx context.Context, username string) ([]fetcher.Mod, error) {
+	return record(ctx, r, fetcher.EndpointMods, username, r.next.FetchMods)
+}
+
+// FetchVips returns a recent VIPs snapshot or fetches and records the VIPs of a channel.
+func (r *Recorder) FetchVips(ctx context.Context, username string) ([]fetcher.Vip, error) {
+	return record(ctx, r, fetcher.EndpointVips, username, r.next.FetchVips)
+}
+
+// FetchFounders returns a recent founders snapshot or fetches and records the founders of a channel.
+func (r *Recorder) FetchFounders(ctx context.Context, username string) ([]fetcher.Founders, error) {
+	return record(ctx, r, fetcher.EndpointFounders, username, r.next.FetchFounders)
+}
+
+// record serves a fresh snapshot if one exists, otherwise fetches the list and saves it.
+// Storage failures are logged and never fail the lookup itself.
+//
+// Parameters:
+//
+//	ctx - Context for the operation
+//	r - Recorder instance
+//	list - List type being fetched
+//	username - Twitch username to fetch data for
+//	fetch - Upstream fetch function
+//
+// Returns:
+//
+//	A slice of items and an error if any
+func record[T any](ctx context.Context, r *Recorder, list fetcher.Endpoint, username string, fetch func(context.Context, string) ([]T, error)) ([]T, error) {
+	const op = "storage.record"
+
+	if r.freshFor > 0 {
+		snap, ok, err := r.store.Latest(username, list)
+		if err != nil {
+			log.Printf("%s: %v", op, err)
+		} else if ok && time.Since(snap.TakenAt) < r.freshFor {
+			if items, err := Decode[T](snap); err == nil {
+				return items, nil
+			}
+		}
+	}
+
+	items, err := fetch(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.store.Save(username, list, time.Now(), items); err != nil {
+		log.Printf("%s: %v", op, err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// liveBucket is the top-level bucket holding go-live registrations keyed by chat ID.
+const liveBucket = "live"
+
+// LiveSubscription is a chat's registration for go-live notifications about
+// the channels a Twitch user follows.
+type LiveSubscription struct {
+	ChatID    int64     `json:"-"`
+	Login     string    `json:"login"`     // Twitch login whose follows are watched
+	Muted     []string  `json:"muted"`     // Channels that never trigger a notification
+	QuietFrom int       `json:"quietFrom"` // Start hour (0-23) of quiet hours in the user's time zone
+	QuietTo   int       `json:"quietTo"`   // End hour (0-23) of quiet hours; equal to QuietFrom disables them
+	UTCOffset int       `json:"utcOffset"` // User's time zone offset from UTC in hours
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SaveLiveSubscription stores a chat's go-live registration, replacing any existing one.
+//
+// Parameters:
+//
+//	sub - Registration to store
+//
+// Returns:
+//
+//	An error if the registration cannot be stored
+func (s *Store) SaveLiveSubscription(sub LiveSubscription) error {
+	const op = "storage.SaveLiveSubscription"
+
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(liveBucket))
+		if err != nil {
+			return err
+		}
+		return root.Put(chatKey(sub.ChatID), data)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteLiveSubscription removes a chat's go-live registration.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//
+// Returns:
+//
+//	Whether a registration existed and an error if any
+func (s *Store) DeleteLiveSubscription(chatID int64) (bool, error) {
+	const op = "storage.DeleteLiveSubscription"
+
+	removed := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(liveBucket))
+		if root == nil || root.Get(chatKey(chatID)) == nil {
+			return nil
+		}
+
+		removed = true
+		return root.Delete(chatKey(chatID))
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return removed, nil
+}
+
+// LiveSubscription returns a chat's go-live registration.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//
+// Returns:
+//
+//	The registration, whether one exists and an error if any
+func (s *Store) LiveSubscription(chatID int64) (LiveSubscription, bool, error) {
+	const op = "storage.LiveSubscription"
+
+	var (
+		sub   LiveSubscription
+		found bool
+	)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(liveBucket))
+		if root == nil {
+			return nil
+		}
+
+		v := root.Get(chatKey(chatID))
+		if v == nil {
+			return nil
+		}
+
+		found = true
+		return json.Unmarshal(v, &sub)
+	})
+	if err != nil {
+		return LiveSubscription{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sub.ChatID = chatID
+	return sub, found, nil
+}
+
+// AllLiveSubscriptions returns the go-live registrations of every chat.
+//
+// Returns:
+//
+//	A slice of registrations and an error if any
+func (s *Store) AllLiveSubscriptions() ([]LiveSubscription, error) {
+	const op = "storage.AllLiveSubscriptions"
+
+	var subs []LiveSubscription
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(liveBucket))
+		if root == nil {
+			return nil
+		}
+
+		return root.ForEach(func(k, v []byte) error {
+			chatID, err := strconv.ParseInt(string(k), 10, 64)
+			if err != nil {
+				return err
+			}
+
+			sub := LiveSubscription{}
+			if err := json.Unmarshal(v, &sub); err != nil {
+				return fmt.Errorf("live subscription %d: %w", chatID, err)
+			}
+			sub.ChatID = chatID
+
+			subs = append(subs, sub)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return subs, nil
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// statesBucket is the top-level bucket holding pending conversation states keyed by "chat:user".
+const statesBucket = "states"
+
+// storedState is an encoded conversation state with its expiry time.
+type storedState struct {
+	Data      json.RawMessage `json:"data"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// SaveState stores an encoded conversation state until expiresAt.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//	userID - Telegram user ID
+//	data - Encoded state
+//	expiresAt - Time after which the state is ignored and pruned
+//
+// Returns:
+//
+//	An error if the state cannot be stored
+func (s *Store) SaveState(chatID, userID int64, data []byte, expiresAt time.Time) error {
+	const op = "storage.SaveState"
+
+	value, err := json.Marshal(storedState{Data: data, ExpiresAt: expiresAt})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(statesBucket))
+		if err != nil {
+			return err
+		}
+		return root.Put(stateKey(chatID, userID), value)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// LoadState returns an unexpired encoded conversation state.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//	userID - Telegram user ID
+//
+// Returns:
+//
+//	The encoded state, whether an unexpired state exists and an error if any
+func (s *Store) LoadState(chatID, userID int64) ([]byte, bool, error) {
+	const op = "storage.LoadState"
+
+	var state storedState
+	found := false
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(statesBucket))
+		if root == nil {
+			return nil
+		}
+
+		v := root.Get(stateKey(chatID, userID))
+		if v == nil {
+			return nil
+		}
+
+		found = true
+		return json.Unmarshal(v, &state)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !found || time.Now().After(state.ExpiresAt) {
+		return nil, false, nil
+	}
+
+	return state.Data, true, nil
+}
+
+// DeleteState removes a conversation state.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//	userID - Telegram user ID
+//
+// Returns:
+//
+//	An error if the state cannot be removed
+func (s *Store) DeleteState(chatID, userID int64) error {
+	const op = "storage.DeleteState"
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(statesBucket))
+		if root == nil {
+			return nil
+		}
+		return root.Delete(stateKey(chatID, userID))
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// pruneStates deletes expired conversation states.
+//
+// Parameters:
+//
+//	tx - Writable transaction
+//	now - Reference time
+//
+// Returns:
+//
+//	The number of deleted states and an error if any
+func pruneStates(tx *bolt.Tx, now time.Time) (int, error) {
+	root := tx.Bucket([]byte(statesBucket))
+	if root == nil {
+		return 0, nil
+	}
+
+	var expired [][]byte
+	err := root.ForEach(func(k, v []byte) error {
+		var state storedState
+		if err := json.Unmarshal(v, &state); err != nil || now.After(state.ExpiresAt) {
+			expired = append(expired, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, k := range expired {
+		if err := root.Delete(k); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
+
+// stateKey encodes a chat and user ID pair as a bucket key.
+func stateKey(chatID, userID int64) []byte {
+	return fmt.Appendf(nil, "%d:%d", chatID, userID)
+}
@@ -0,0 +1,480 @@
+// Package storage persists fetcher results as timestamped snapshots in a bbolt database.
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Snapshot is a stored fetcher result for a channel and list type.
+type Snapshot struct {
+	Channel string           // Normalized Twitch channel name
+	List    fetcher.Endpoint // List type the snapshot belongs to
+	TakenAt time.Time        // Time the data was fetched
+	Data    json.RawMessage  // JSON-encoded slice of fetcher structs
+}
+
+// RetentionPolicy limits how many snapshots are kept per channel and list type.
+type RetentionPolicy struct {
+	MaxAge       time.Duration // Snapshots older than this are deleted, zero keeps them forever
+	MaxSnapshots int           // Newest snapshots kept, zero for unlimited
+
+	// PruneInterval is the time between sweeps of every channel and list type,
+	// so channels that are no longer saved expire too. Zero prunes only on open.
+	PruneInterval time.Duration
+}
+
+// reservedBuckets lists top-level buckets that do not hold list snapshots.
+var reservedBuckets = map[string]bool{
+	subscriptionsBucket: true,
+	liveBucket:          true,
+	statesBucket:        true,
+	accessBucket:        true,
+}
+
+// Store persists snapshots in a bbolt database. The database contains one
+// top-level bucket per list type with a nested bucket per channel, whose keys
+// are big-endian UnixNano timestamps.
+type Store struct {
+	db        *bolt.DB
+	retention RetentionPolicy
+	stop      chan struct{} // Closed to stop the periodic pruning
+	stopped   chan struct{} // Closed once the periodic pruning has stopped
+	closeOnce sync.Once
+}
+
+// Open opens or creates the snapshot database at path, prunes expired snapshots
+// and keeps pruning them every retention.PruneInterval until the store is closed.
+//
+// Parameters:
+//
+//	path - Database file path
+//	retention - Retention policy applied on every save
+//
+// Returns:
+//
+//	A pointer to a new Store instance and an error if any
+func Open(path string, retention RetentionPolicy) (*Store, error) {
+	const op = "storage.Open"
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s := &Store{
+		db:        db,
+		retention: retention,
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	if _, err := s.Prune(time.Now()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	go s.runPrune()
+
+	return s, nil
+}
+
+// Close stops the periodic pruning and closes the underlying database.
+// Closing a closed store does nothing.
+//
+// Returns:
+//
+//	An error if the database cannot be closed
+func (s *Store) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	<-s.stopped
+
+	return s.db.Close()
+}
+
+// runPrune applies the retention policy every PruneInterval until the store is closed.
+func (s *Store) runPrune() {
+	defer close(s.stopped)
+
+	if s.retention.PruneInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.retention.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if _, err := s.Prune(now); err != nil {
+				log.Printf("storage.runPrune: %v", err)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Save stores items as a new snapshot and applies the retention policy to
+// the channel's history for that list type. Of a run of identical snapshots
+// only the first and the last are kept, so frequent polls of an unchanged list
+// do not crowd older history out of the retention limit.
+//
+// Parameters:
+//
+//	channel - Twitch channel name
+//	list - List type of the items
+//	takenAt - Time the items were fetched
+//	items - Slice of fetcher structs to store
+//
+// Returns:
+//
+//	An error if the snapshot cannot be stored
+func (s *Store) Save(channel string, list fetcher.Endpoint, takenAt time.Time, items any) error {
+	const op = "storage.Save"
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(list))
+		if err != nil {
+			return err
+		}
+
+		bucket, err := root.CreateBucketIfNotExists([]byte(utils.NormalizeUsername(channel)))
+		if err != nil {
+			return err
+		}
+
+		if err := compact(bucket, takenAt, data); err != nil {
+			return err
+		}
+
+		if err := bucket.Put(timeKey(takenAt), data); err != nil {
+			return err
+		}
+
+		_, err = s.prune(bucket, takenAt)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Latest returns the newest snapshot of a channel's list.
+//
+// Parameters:
+//
+//	channel - Twitch channel name
+//	list - List type to look up
+//
+// Returns:
+//
+//	The snapshot, whether one exists and an error if any
+func (s *Store) Latest(channel string, list fetcher.Endpoint) (Snapshot, bool, error) {
+	return s.At(channel, list, time.Now())
+}
+
+// At returns the newest snapshot taken at or before t.
+//
+// Parameters:
+//
+//	channel - Twitch channel name
+//	list - List type to look up
+//	t - Point in time to look up
+//
+// Returns:
+//
+//	The snapshot, whether one exists and an error if any
+func (s *Store) At(channel string, list fetcher.Endpoint, t time.Time) (Snapshot, bool, error) {
+	const op = "storage.At"
+
+	var (
+		snap  Snapshot
+		found bool
+	)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := channelBucket(tx, channel, list)
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		target := timeKey(t)
+
+		k, v := c.Seek(target)
+		if k == nil || !bytes.Equal(k, target) {
+			k, v = c.Prev()
+		}
+		if k == nil {
+			return nil
+		}
+
+		snap = newSnapshot(channel, list, k, v)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return Snapshot{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snap, found, nil
+}
+
+// Oldest returns the oldest stored snapshot of a channel's list.
+//
+// Parameters:
+//
+//	channel - Twitch channel name
+//	list - List type to look up
+//
+// Returns:
+//
+//	The snapshot, whether one exists and an error if any
+func (s *Store) Oldest(channel string, list fetcher.Endpoint) (Snapshot, bool, error) {
+	const op = "storage.Oldest"
+
+	var (
+		snap  Snapshot
+		found bool
+	)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := channelBucket(tx, channel, list)
+		if bucket == nil {
+			return nil
+		}
+
+		k, v := bucket.Cursor().First()
+		if k == nil {
+			return nil
+		}
+
+		snap = newSnapshot(channel, list, k, v)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return Snapshot{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snap, found, nil
+}
+
+// History returns all snapshots of a channel's list taken within [from, to], oldest first.
+//
+// Parameters:
+//
+//	channel - Twitch channel name
+//	list - List type to look up
+//	from - Start of the time range
+//	to - End of the time range
+//
+// Returns:
+//
+//	A slice of snapshots and an error if any
+func (s *Store) History(channel string, list fetcher.Endpoint, from, to time.Time) ([]Snapshot, error) {
+	const op = "storage.History"
+
+	var snaps []Snapshot
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := channelBucket(tx, channel, list)
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		end := timeKey(to)
+		for k, v := c.Seek(timeKey(from)); k != nil && bytes.Compare(k, end) <= 0; k, v = c.Next() {
+			snaps = append(snaps, newSnapshot(channel, list, k, v))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snaps, nil
+}
+
+// Prune applies the retention policy to every stored channel and list type,
+// removes channel buckets left empty and removes expired conversation states.
+//
+// Parameters:
+//
+//	now - Reference time for the maximum age
+//
+// Returns:
+//
+//	The number of deleted snapshots and an error if any
+func (s *Store) Prune(now time.Time) (int, error) {
+	const op = "storage.Prune"
+
+	deleted := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		n, err := pruneStates(tx, now)
+		if err != nil {
+			return err
+		}
+		deleted += n
+
+		return tx.ForEach(func(name []byte, root *bolt.Bucket) error {
+			if reservedBuckets[string(name)] {
+				return nil
+			}
+			var empty [][]byte
+			err := root.ForEachBucket(func(name []byte) error {
+				bucket := root.Bucket(name)
+				n, err := s.prune(bucket, now)
+				deleted += n
+				if k, _ := bucket.Cursor().First(); k == nil {
+					empty = append(empty, bytes.Clone(name))
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, name := range empty {
+				if err := root.DeleteBucket(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return deleted, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// prune deletes snapshots of a single channel bucket that violate the retention policy.
+//
+// Parameters:
+//
+//	bucket - Channel bucket
+//	now - Reference time for the maximum age
+//
+// Returns:
+//
+//	The number of deleted snapshots and an error if any
+func (s *Store) prune(bucket *bolt.Bucket, now time.Time) (int, error) {
+	var expired [][]byte
+
+	total := 0
+	c := bucket.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		total++
+	}
+
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		tooOld := s.retention.MaxAge > 0 && now.Sub(keyTime(k)) > s.retention.MaxAge
+		tooMany := s.retention.MaxSnapshots > 0 && total-len(expired) > s.retention.MaxSnapshots
+		if !tooOld && !tooMany {
+			break
+		}
+		expired = append(expired, k)
+	}
+
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
+
+// compact deletes the newest snapshot of a bucket if it and the one before it
+// both equal data, so the snapshot about to be stored extends their run.
+//
+// Parameters:
+//
+//	bucket - Channel bucket the snapshot is stored in
+//	takenAt - Time of the snapshot about to be stored
+//	data - Encoded snapshot about to be stored
+//
+// Returns:
+//
+//	An error if the snapshot cannot be deleted
+func compact(bucket *bolt.Bucket, takenAt time.Time, data []byte) error {
+	c := bucket.Cursor()
+
+	last, lastData := c.Last()
+	if last == nil || !keyTime(last).Before(takenAt) || !bytes.Equal(lastData, data) {
+		return nil
+	}
+
+	if prev, prevData := c.Prev(); prev == nil || !bytes.Equal(prevData, data) {
+		return nil
+	}
+
+	return bucket.Delete(last)
+}
+
+// Decode unmarshals a snapshot's data into a slice of fetcher structs.
+//
+// Parameters:
+//
+//	snap - Snapshot to decode
+//
+// Returns:
+//
+//	A slice of decoded items and an error if any
+func Decode[T any](snap Snapshot) ([]T, error) {
+	var items []T
+	if err := json.Unmarshal(snap.Data, &items); err != nil {
+		return nil, fmt.Errorf("storage.Decode: %s/%s: %w", snap.List, snap.Channel, err)
+	}
+	return items, nil
+}
+
+// channelBucket returns the nested bucket of a channel's list, or nil if none exists.
+func channelBucket(tx *bolt.Tx, channel string, list fetcher.Endpoint) *bolt.Bucket {
+	root := tx.Bucket([]byte(list))
+	if root == nil {
+		return nil
+	}
+	return root.Bucket([]byte(utils.NormalizeUsername(channel)))
+}
+
+// newSnapshot builds a Snapshot from a bucket key and value, copying the value
+// because bbolt memory is only valid inside the transaction.
+func newSnapshot(channel string, list fetcher.Endpoint, k, v []byte) Snapshot {
+	return Snapshot{
+		Channel: utils.NormalizeUsername(channel),
+		List:    list,
+		TakenAt: keyTime(k),
+		Data:    bytes.Clone(v),
+	}
+}
+
+// timeKey encodes a timestamp as a sortable bucket key.
+func timeKey(t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano()))
+}
+
+// keyTime decodes a bucket key produced by timeKey.
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	bolt "go.etcd.io/bbolt"
+)
+
+// openTestStore opens a store in a temporary directory that is closed when the test ends.
+func openTestStore(t *testing.T, retention RetentionPolicy) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "test.db"), retention)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// mods returns a mod list with one mod per login.
+func mods(logins ...string) []fetcher.Mod {
+	out := make([]fetcher.Mod, 0, len(logins))
+	for _, login := range logins {
+		out = append(out, fetcher.Mod{ID: login, Login: login, DisplayName: login})
+	}
+	return out
+}
+
+// snapshotTimes returns the times of a channel's mod snapshots in order.
+func snapshotTimes(t *testing.T, s *Store, channel string) []time.Time {
+	t.Helper()
+
+	snaps, err := s.History(channel, fetcher.EndpointMods, time.Unix(0, 0), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+
+	times := make([]time.Time, 0, len(snaps))
+	for _, snap := range snaps {
+		times = append(times, snap.TakenAt)
+	}
+	return times
+}
+
+func TestSaveCompactsIdenticalSnapshots(t *testing.T) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name  string
+		saves [][]fetcher.Mod // Saved a minute apart
+		want  []time.Time
+	}{
+		{
+			name:  "changed lists are all kept",
+			saves: [][]fetcher.Mod{mods("a"), mods("a", "b"), mods("b")},
+			want:  []time.Time{at(0), at(1), at(2)},
+		},
+		{
+			name:  "two identical snapshots are kept",
+			saves: [][]fetcher.Mod{mods("a"), mods("a")},
+			want:  []time.Time{at(0), at(1)},
+		},
+		{
+			name:  "a run keeps its first and last snapshot",
+			saves: [][]fetcher.Mod{mods("a"), mods("a"), mods("a"), mods("a")},
+			want:  []time.Time{at(0), at(3)},
+		},
+		{
+			name:  "runs on both sides of a change",
+			saves: [][]fetcher.Mod{mods("a"), mods("a"), mods("a"), mods("b"), mods("b"), mods("b")},
+			want:  []time.Time{at(0), at(2), at(3), at(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openTestStore(t, RetentionPolicy{})
+
+			for i, items := range tt.saves {
+				if err := s.Save("xqc", fetcher.EndpointMods, at(i), items); err != nil {
+					t.Fatalf("Save %d: %v", i, err)
+				}
+			}
+
+			got := snapshotTimes(t, s, "xqc")
+			if len(got) != len(tt.want) {
+				t.Fatalf("snapshots at %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("snapshot %d at %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSaveKeepsHistoryOfUnchangedLists(t *testing.T) {
+	s := openTestStore(t, RetentionPolicy{MaxSnapshots: 3})
+	start := time.Now().Add(-time.Hour)
+
+	if err := s.Save("xqc", fetcher.EndpointMods, start, mods("a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	for i := 1; i <= 10; i++ {
+		if err := s.Save("xqc", fetcher.EndpointMods, start.Add(time.Duration(i)*time.Minute), mods("a", "b")); err != nil {
+			t.Fatalf("Save %d: %v", i, err)
+		}
+	}
+
+	// Polls of the unchanged list must not push the older version out.
+	snap, ok, err := s.Oldest("xqc", fetcher.EndpointMods)
+	if err != nil || !ok {
+		t.Fatalf("Oldest: %t, %v", ok, err)
+	}
+	items, err := Decode[fetcher.Mod](snap)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(items) != 1 || items[0].Login != "a" {
+		t.Errorf("oldest snapshot holds %v, want the first list", items)
+	}
+}
+
+func TestPeriodicPruneRemovesIdleChannels(t *testing.T) {
+	s := openTestStore(t, RetentionPolicy{MaxAge: 200 * time.Millisecond, PruneInterval: 20 * time.Millisecond})
+
+	if err := s.Save("idle", fetcher.EndpointMods, time.Now(), mods("a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.SaveState(1, 2, []byte("{}"), time.Now().Add(100*time.Millisecond)); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	// Nothing is saved for the channel again, so only the periodic pruning can expire it.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, ok, err := s.Oldest("idle", fetcher.EndpointMods)
+		if err != nil {
+			t.Fatalf("Oldest: %v", err)
+		}
+		if !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("snapshot of an idle channel outlived MaxAge")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if root := tx.Bucket([]byte(fetcher.EndpointMods)); root != nil && root.Bucket([]byte("idle")) != nil {
+			t.Error("empty channel bucket was kept")
+		}
+		if states := tx.Bucket([]byte(statesBucket)); states != nil && states.Stats().KeyN > 0 {
+			t.Error("expired state was kept")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+}
+
+func TestPruneOnlyOnOpenWithoutInterval(t *testing.T) {
+	s := openTestStore(t, RetentionPolicy{MaxAge: time.Hour})
+
+	if err := s.Save("idle", fetcher.EndpointMods, time.Now().Add(-2*time.Hour), mods("a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	deleted, err := s.Prune(time.Now())
+	if err != nil || deleted != 1 {
+		t.Errorf("Prune() = %d, %v, want 1 deleted", deleted, err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
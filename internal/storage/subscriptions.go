@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kirinyoku/twitch-kit/internal/fetcher"
+	"github.com/kirinyoku/twitch-kit/internal/utils"
+	bolt "go.etcd.io/bbolt"
+)
+
+// subscriptionsBucket is the top-level bucket holding watchlist subscriptions,
+// with a nested bucket per chat ID keyed by channel name.
+const subscriptionsBucket = "subscriptions"
+
+// Subscription is a chat's request to be notified about changes to a channel's lists.
+type Subscription struct {
+	ChatID    int64              `json:"-"`
+	Channel   string             `json:"-"`
+	Lists     []fetcher.Endpoint `json:"lists"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// Subscribe stores a subscription, replacing the chat's existing subscription to the same channel.
+//
+// Parameters:
+//
+//	sub - Subscription to store
+//
+// Returns:
+//
+//	An error if the subscription cannot be stored
+func (s *Store) Subscribe(sub Subscription) error {
+	const op = "storage.Subscribe"
+
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(subscriptionsBucket))
+		if err != nil {
+			return err
+		}
+
+		chat, err := root.CreateBucketIfNotExists(chatKey(sub.ChatID))
+		if err != nil {
+			return err
+		}
+
+		return chat.Put([]byte(utils.NormalizeUsername(sub.Channel)), data)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Unsubscribe removes a chat's subscription to a channel.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//	channel - Twitch channel name
+//
+// Returns:
+//
+//	Whether a subscription existed and an error if any
+func (s *Store) Unsubscribe(chatID int64, channel string) (bool, error) {
+	const op = "storage.Unsubscribe"
+
+	removed := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(subscriptionsBucket))
+		if root == nil {
+			return nil
+		}
+
+		chat := root.Bucket(chatKey(chatID))
+		if chat == nil {
+			return nil
+		}
+
+		key := []byte(utils.NormalizeUsername(channel))
+		if chat.Get(key) == nil {
+			return nil
+		}
+
+		removed = true
+		return chat.Delete(key)
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return removed, nil
+}
+
+// Subscriptions returns all subscriptions of a chat ordered by channel name.
+//
+// Parameters:
+//
+//	chatID - Telegram chat ID
+//
+// Returns:
+//
+//	A slice of subscriptions and an error if any
+func (s *Store) Subscriptions(chatID int64) ([]Subscription, error) {
+	const op = "storage.Subscriptions"
+
+	var subs []Subscription
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(subscriptionsBucket))
+		if root == nil {
+			return nil
+		}
+
+		chat := root.Bucket(chatKey(chatID))
+		if chat == nil {
+			return nil
+		}
+
+		return chat.ForEach(func(k, v []byte) error {
+			sub, err := decodeSubscription(chatID, k, v)
+			subs = append(subs, sub)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return subs, nil
+}
+
+// AllSubscriptions returns the subscriptions of every chat.
+//
+// Returns:
+//
+//	A slice of subscriptions and an error if any
+func (s *Store) AllSubscriptions() ([]Subscription, error) {
+	const op = "storage.AllSubscriptions"
+
+	var subs []Subscription
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(subscriptionsBucket))
+		if root == nil {
+			return nil
+		}
+
+		return root.ForEachBucket(func(name []byte) error {
+			chatID, err := strconv.ParseInt(string(name), 10, 64)
+			if err != nil {
+				return err
+			}
+
+			return root.Bucket(name).ForEach(func(k, v []byte) error {
+				sub, err := decodeSubscription(chatID, k, v)
+				subs = append(subs, sub)
+				return err
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return subs, nil
+}
+
+// decodeSubscription builds a Subscription from a chat bucket entry.
+func decodeSubscription(chatID int64, k, v []byte) (Subscription, error) {
+	sub := Subscription{ChatID: chatID, Channel: string(k)}
+	if err := json.Unmarshal(v, &sub); err != nil {
+		return sub, fmt.Errorf("subscription %d/%s: %w", chatID, k, err)
+	}
+	return sub, nil
+}
+
+// chatKey encodes a chat ID as a bucket name.
+func chatKey(chatID int64) []byte {
+	return []byte(strconv.FormatInt(chatID, 10))
+}
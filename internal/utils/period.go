@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePeriod converts a human-friendly period into a duration.
+// Besides Go durations ("36h") it accepts day and week suffixes ("7d", "2w").
+//
+// Parameters:
+//
+//	text - The period to parse
+//
+// Returns:
+//
+//	The parsed duration and an error if the period is invalid or not positive
+func ParsePeriod(text string) (time.Duration, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(text, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid period: %q", text)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(text)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period: %q", text)
+	}
+
+	return d, nil
+}
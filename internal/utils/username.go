@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+const (
+	MinUsernameLength = 3  // New accounts need 4 characters, but some legacy logins are shorter
+	MaxUsernameLength = 25 // Maximum length of a Twitch login
+)
+
+var (
+	ErrUsernameEmpty  = errors.New("username is empty")
+	ErrUsernameLength = fmt.Errorf("username must be %d-%d characters long", MinUsernameLength, MaxUsernameLength)
+	ErrUsernameChars  = errors.New("username may only contain letters, digits and underscores")
+	ErrNotChannelURL  = errors.New("link does not point to a Twitch channel")
+)
+
+// twitchHosts lists the hosts of twitch.tv links that carry a channel name.
+var twitchHosts = map[string]bool{
+	"twitch.tv":     true,
+	"www.twitch.tv": true,
+	"m.twitch.tv":   true,
+}
+
+// channelPrefixes lists first path segments followed by a channel name,
+// e.g. "twitch.tv/popout/xqc/chat".
+var channelPrefixes = map[string]bool{
+	"popout":    true,
+	"moderator": true,
+}
+
+// reservedPaths lists first path segments of twitch.tv pages that are not channels.
+var reservedPaths = map[string]bool{
+	"directory":     true,
+	"downloads":     true,
+	"drops":         true,
+	"friends":       true,
+	"inventory":     true,
+	"jobs":          true,
+	"login":         true,
+	"messages":      true,
+	"p":             true,
+	"payments":      true,
+	"search":        true,
+	"settings":      true,
+	"signup":        true,
+	"subscriptions": true,
+	"turbo":         true,
+	"videos":        true,
+	"wallet":        true,
+}
+
+// NormalizeUsername trims whitespace and a leading "@" and lowercases a login.
+// It does not validate the result; use ParseUsername for user input.
+//
+// Parameters:
+//
+//	username - The login to normalize
+//
+// Returns:
+//
+//	The normalized login
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
+
+// ParseUsername extracts a Twitch login from user input. It accepts plain
+// logins, @mentions and twitch.tv links such as "https://m.twitch.tv/xqc",
+// "twitch.tv/xqc/videos" or "twitch.tv/xqc/clip/<slug>".
+//
+// Parameters:
+//
+//	input - Text entered by the user
+//
+// Returns:
+//
+//	The lowercased login and an error wrapping one of the ErrUsername*
+//	or ErrNotChannelURL errors if the input is not a valid login
+func ParseUsername(input string) (string, error) {
+	text := strings.TrimSpace(input)
+
+	if isLink(text) {
+		login, err := channelFromURL(text)
+		if err != nil {
+			return "", fmt.Errorf("%q: %w", input, err)
+		}
+		text = login
+	}
+
+	username := NormalizeUsername(text)
+	if err := ValidateUsername(username); err != nil {
+		return "", fmt.Errorf("%q: %w", input, err)
+	}
+
+	return username, nil
+}
+
+// ValidateUsername checks a lowercased login against Twitch login rules.
+//
+// Parameters:
+//
+//	username - The login to check
+//
+// Returns:
+//
+//	ErrUsernameEmpty, ErrUsernameLength or ErrUsernameChars if the login is invalid, nil otherwise
+func ValidateUsername(username string) error {
+	if username == "" {
+		return ErrUsernameEmpty
+	}
+
+	for _, r := range username {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return ErrUsernameChars
+		}
+	}
+
+	if len(username) < MinUsernameLength || len(username) > MaxUsernameLength {
+		return ErrUsernameLength
+	}
+
+	return nil
+}
+
+// isLink reports whether text looks like a link rather than a login.
+func isLink(text string) bool {
+	if strings.Contains(text, "://") {
+		return true
+	}
+
+	host, _, _ := strings.Cut(strings.ToLower(text), "/")
+	return host == "clips.twitch.tv" || twitchHosts[host]
+}
+
+// channelFromURL returns the channel segment of a twitch.tv link.
+func channelFromURL(link string) (string, error) {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", ErrNotChannelURL
+	}
+
+	if !twitchHosts[strings.ToLower(u.Hostname())] {
+		return "", ErrNotChannelURL
+	}
+
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	if len(segments) > 0 && channelPrefixes[strings.ToLower(segments[0])] {
+		segments = segments[1:]
+	}
+
+	if len(segments) == 0 || reservedPaths[strings.ToLower(segments[0])] {
+		return "", ErrNotChannelURL
+	}
+
+	return segments[0], nil
+}
+
+// SplitUsernames splits a list of channels separated by commas, semicolons,
+// spaces or newlines, dropping empty entries and duplicates. Entries naming the
+// same channel, e.g. "xqc" and "twitch.tv/xqc", are duplicates; entries that
+// are not valid channels are compared case-insensitively.
+//
+// Parameters:
+//
+//	text - The list entered by the user
+//
+// Returns:
+//
+//	The first entry of each channel in the original order, not yet validated
+func SplitUsernames(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+
+	seen := make(map[string]bool, len(fields))
+	var names []string
+	for _, field := range fields {
+		key, err := ParseUsername(field)
+		if err != nil {
+			key = NormalizeUsername(field)
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, field)
+	}
+
+	return names
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseUsername(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"plain login", "xqc", "xqc", nil},
+		{"mixed case and whitespace", "  XQc \n", "xqc", nil},
+		{"mention", "@xqc", "xqc", nil},
+		{"underscores and digits", "a_1_b", "a_1_b", nil},
+		{"shortest legacy login", "abc", "abc", nil},
+		{"longest login", "abcdefghijklmnopqrstuvwxy", "abcdefghijklmnopqrstuvwxy", nil},
+		{"link", "https://twitch.tv/xqc", "xqc", nil},
+		{"www link", "https://www.twitch.tv/xqc", "xqc", nil},
+		{"mobile link", "https://m.twitch.tv/XQC", "xqc", nil},
+		{"link without a scheme", "twitch.tv/xqc", "xqc", nil},
+		{"www link without a scheme", "www.twitch.tv/xqc", "xqc", nil},
+		{"trailing slash", "https://twitch.tv/xqc/", "xqc", nil},
+		{"query string", "https://twitch.tv/xqc?referrer=raid", "xqc", nil},
+		{"fragment", "https://twitch.tv/xqc#chat", "xqc", nil},
+		{"channel subpage", "https://twitch.tv/xqc/videos", "xqc", nil},
+		{"clip of a channel", "https://www.twitch.tv/xqc/clip/SomeSlug-abc", "xqc", nil},
+		{"popout chat", "https://www.twitch.tv/popout/xqc/chat", "xqc", nil},
+		{"moderator view", "twitch.tv/moderator/xqc", "xqc", nil},
+
+		{"empty", "", "", ErrUsernameEmpty},
+		{"only whitespace", "   ", "", ErrUsernameEmpty},
+		{"only a mention sign", "@", "", ErrUsernameEmpty},
+		{"too short", "ab", "", ErrUsernameLength},
+		{"too long", "abcdefghijklmnopqrstuvwxyz", "", ErrUsernameLength},
+		{"hyphen", "x-qc", "", ErrUsernameChars},
+		{"non-latin letters", "привет", "", ErrUsernameChars},
+		{"dot", "x.qc", "", ErrUsernameChars},
+		{"link without a channel", "https://twitch.tv/", "", ErrNotChannelURL},
+		{"reserved path", "https://www.twitch.tv/directory/category/chess", "", ErrNotChannelURL},
+		{"settings page", "twitch.tv/settings", "", ErrNotChannelURL},
+		{"popout without a channel", "twitch.tv/popout", "", ErrNotChannelURL},
+		{"clips host", "https://clips.twitch.tv/SomeSlug", "", ErrNotChannelURL},
+		{"other site", "https://youtube.com/xqc", "", ErrNotChannelURL},
+		{"invalid login in a link", "https://twitch.tv/x-qc", "", ErrUsernameChars},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUsername(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseUsername(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseUsername(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitUsernames(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"only separators", " ,;\n ", nil},
+		{"every separator", "xqc,shroud;summit1g\nlirik\ttarik pokimane", []string{"xqc", "shroud", "summit1g", "lirik", "tarik", "pokimane"}},
+		{"empty entries", "xqc,,  ,shroud", []string{"xqc", "shroud"}},
+		{"duplicates ignoring case", "xqc XQC @Xqc", []string{"xqc"}},
+		{"duplicates as links", "xqc twitch.tv/xqc https://www.twitch.tv/xqc m.twitch.tv/XQC/videos", []string{"xqc"}},
+		{"first entry of a channel is kept", "https://twitch.tv/xqc shroud xqc", []string{"https://twitch.tv/xqc", "shroud"}},
+		{"invalid entries are kept once", "x-qc X-QC shroud", []string{"x-qc", "shroud"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitUsernames(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("SplitUsernames(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
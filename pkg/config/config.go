@@ -4,13 +4,150 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config represents the application configuration.
 type Config struct {
-	TelegramToken string
+	TelegramToken   string
+	Workers         int           // Number of updates processed concurrently
+	QueueSize       int           // Maximum number of queued and in-flight updates
+	ShutdownTimeout time.Duration // Time limit for finishing in-flight work on shutdown
+	CrashChatID     int64         // Chat receiving reports of recovered panics, zero to only log them
+	AdminIDs        []int64       // Telegram user IDs allowed to manage the access lists
+	Batch           BatchConfig
+	Pages           PagesConfig
+	State           StateConfig
+	Fetcher         FetcherConfig
+	Cache           CacheConfig
+	Storage         StorageConfig
+	Watch           WatchConfig
+	Live            LiveConfig
+	Webhook         WebhookConfig
+	Send            SendConfig
+	RateLimit       RateLimitConfig
+}
+
+// RateLimitConfig represents the limits of incoming requests per user and chat.
+// A zero burst disables the corresponding token bucket.
+type RateLimitConfig struct {
+	UserBurst    int           // Requests a user may send at once
+	UserInterval time.Duration // Time for a user to regain one request
+	ChatBurst    int           // Requests a chat may send at once
+	ChatInterval time.Duration // Time for a chat to regain one request
+	DailyQuota   int           // Requests per user per UTC day, zero for unlimited
+}
+
+// SendConfig represents the rate limits of outgoing Telegram messages.
+type SendConfig struct {
+	GlobalPerSecond int           // Messages per second across all chats
+	ChatInterval    time.Duration // Minimum time between messages to one chat
+	GroupPerMinute  int           // Messages per minute to one group chat
+	MaxAttempts     int           // Attempts per message on transient failures
+}
+
+// WebhookConfig represents the Telegram webhook configuration.
+// Updates are received through long polling when URL is empty.
+type WebhookConfig struct {
+	URL            string // Public HTTPS base URL Telegram sends updates to
+	Listen         string // Address of the embedded HTTP server
+	PathSecret     string // Secret path segment the webhook is served under
+	SecretToken    string // Expected X-Telegram-Bot-Api-Secret-Token header value
+	CertFile       string // TLS certificate file, empty to serve plain HTTP behind a reverse proxy
+	KeyFile        string // TLS private key file
+	UploadCert     bool   // Whether the certificate is uploaded to Telegram (self-signed certificates)
+	MaxConnections int    // Maximum simultaneous connections from Telegram
+}
+
+// StateConfig represents the conversation state store configuration.
+type StateConfig struct {
+	TTL        time.Duration // Time after which an unanswered prompt is forgotten
+	Persistent bool          // Whether states are kept in storage to survive restarts
+}
+
+// BatchConfig represents the limits of lookups of several channels at once.
+type BatchConfig struct {
+	MaxChannels int // Maximum number of channels in one lookup
+	Concurrency int // Number of channels fetched at the same time
+}
+
+// PagesConfig represents the paginated result view configuration.
+type PagesConfig struct {
+	TTL        time.Duration // Time a paginated result can be browsed
+	MaxResults int           // Maximum number of paginated results kept in memory
+}
+
+// FetcherConfig represents the upstream API client configuration.
+type FetcherConfig struct {
+	BaseURL   string                 // Upstream API base URL
+	UserAgent string                 // User-Agent header sent with every request
+	Timeout   time.Duration          // HTTP client timeout per request
+	Paths     map[string]string      // Endpoint name to path template overrides
+	Retry     RetryConfig            // Default retry policy
+	Retries   map[string]RetryConfig // Endpoint name to retry policy
+}
+
+// CacheConfig represents the in-memory fetcher cache configuration.
+type CacheConfig struct {
+	TTL        time.Duration // Lifetime of a cached result, zero disables the cache
+	MaxEntries int           // Maximum number of cached results, zero for unlimited
+}
+
+// StorageConfig represents the persistent snapshot store configuration.
+type StorageConfig struct {
+	Path          string        // Database file path, empty disables persistence
+	FreshFor      time.Duration // Maximum snapshot age served without an upstream call
+	MaxAge        time.Duration // Snapshots older than this are deleted, zero keeps them forever
+	MaxSnapshots  int           // Snapshots kept per channel and list type, zero for unlimited
+	PruneInterval time.Duration // Time between retention sweeps, zero prunes only on start
+}
+
+// WatchConfig represents the watchlist scheduler configuration.
+type WatchConfig struct {
+	Interval   time.Duration // Time between polls of every watched channel list
+	MaxPerChat int           // Maximum number of watched channels per chat, zero for unlimited
+}
+
+// LiveConfig represents the go-live notifier configuration.
+type LiveConfig struct {
+	Interval   time.Duration // Time between polls of every registered login's follows
+	MaxPerHour int           // Maximum notifications per chat per hour, zero for unlimited
+}
+
+// RetryConfig represents the retry policy for transient upstream failures.
+type RetryConfig struct {
+	MaxAttempts     int           // Total attempts including the first one
+	BaseDelay       time.Duration // Delay before the first retry
+	MaxDelay        time.Duration // Upper bound for a single backoff delay
+	Jitter          float64       // Fraction (0..1) of each delay that is randomized
+	RetryableStatus []int         // Status codes that trigger a retry
+}
+
+// defaultRetry is the retry policy used when no retry variables are set.
+var defaultRetry = RetryConfig{
+	MaxAttempts:     3,
+	BaseDelay:       200 * time.Millisecond,
+	MaxDelay:        2 * time.Second,
+	Jitter:          0.2,
+	RetryableStatus: []int{429, 500, 502, 503, 504},
+}
+
+var (
+	webhookPathPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)       // Allowed WEBHOOK_PATH_SECRET values
+	secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`) // Allowed WEBHOOK_SECRET_TOKEN values
+)
+
+// fetcherPathVars maps endpoint names to the environment variables overriding their paths.
+var fetcherPathVars = map[string]string{
+	"follows":  "FETCHER_PATH_FOLLOWS",
+	"mods":     "FETCHER_PATH_MODS",
+	"vips":     "FETCHER_PATH_VIPS",
+	"founders": "FETCHER_PATH_FOUNDERS",
 }
 
 // Load reads the configuration from the environment variables and returns a Config object.
@@ -19,8 +156,259 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	timeout, err := getDuration("FETCHER_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTTL, err := getDuration("CACHE_TTL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheMaxEntries, err := getInt("CACHE_MAX_ENTRIES", 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	storageFreshFor, err := getDuration("STORAGE_FRESH_FOR", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	storageMaxAge, err := getDuration("STORAGE_MAX_AGE", 90*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	storageMaxSnapshots, err := getInt("STORAGE_MAX_SNAPSHOTS", 500)
+	if err != nil {
+		return nil, err
+	}
+
+	storagePruneInterval, err := getDuration("STORAGE_PRUNE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	watchInterval, err := getDuration("WATCH_INTERVAL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	watchMaxPerChat, err := getInt("WATCH_MAX_PER_CHAT", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	liveInterval, err := getDuration("LIVE_INTERVAL", 3*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	liveMaxPerHour, err := getInt("LIVE_MAX_PER_HOUR", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := getInt("WORKERS", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	queueSize, err := getInt("QUEUE_SIZE", 256)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := getDuration("SHUTDOWN_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	crashChatID, err := getInt("CRASH_REPORT_CHAT_ID", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sendGlobalPerSecond, err := getInt("SEND_GLOBAL_PER_SECOND", 30)
+	if err != nil {
+		return nil, err
+	}
+
+	sendChatInterval, err := getDuration("SEND_CHAT_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	sendGroupPerMinute, err := getInt("SEND_GROUP_PER_MINUTE", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	sendMaxAttempts, err := getInt("SEND_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	adminIDs, err := getIntList("ADMIN_USER_IDS", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rateUserBurst, err := getInt("RATE_USER_BURST", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	rateUserInterval, err := getDuration("RATE_USER_INTERVAL", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	rateChatBurst, err := getInt("RATE_CHAT_BURST", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	rateChatInterval, err := getDuration("RATE_CHAT_INTERVAL", 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	rateDailyQuota, err := getInt("RATE_DAILY_QUOTA", 500)
+	if err != nil {
+		return nil, err
+	}
+
+	batchMaxChannels, err := getInt("BATCH_MAX_CHANNELS", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	batchConcurrency, err := getInt("BATCH_CONCURRENCY", 4)
+	if err != nil {
+		return nil, err
+	}
+
+	pageTTL, err := getDuration("PAGE_TTL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	pageMaxResults, err := getInt("PAGE_MAX_RESULTS", 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	stateTTL, err := getDuration("STATE_TTL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	statePersistent, err := getBool("STATE_PERSISTENT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookUploadCert, err := getBool("WEBHOOK_UPLOAD_CERT", false)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookMaxConnections, err := getInt("WEBHOOK_MAX_CONNECTIONS", 40)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
-		TelegramToken: os.Getenv("TELEGRAM_TOKEN"),
+		TelegramToken:   os.Getenv("TELEGRAM_TOKEN"),
+		Workers:         workers,
+		QueueSize:       queueSize,
+		ShutdownTimeout: shutdownTimeout,
+		CrashChatID:     int64(crashChatID),
+		AdminIDs:        make([]int64, 0, len(adminIDs)),
+		Batch: BatchConfig{
+			MaxChannels: batchMaxChannels,
+			Concurrency: batchConcurrency,
+		},
+		Pages: PagesConfig{
+			TTL:        pageTTL,
+			MaxResults: pageMaxResults,
+		},
+		State: StateConfig{
+			TTL:        stateTTL,
+			Persistent: statePersistent,
+		},
+		Fetcher: FetcherConfig{
+			BaseURL:   os.Getenv("FETCHER_BASE_URL"),
+			UserAgent: os.Getenv("FETCHER_USER_AGENT"),
+			Timeout:   timeout,
+			Paths:     make(map[string]string),
+		},
+		Cache: CacheConfig{
+			TTL:        cacheTTL,
+			MaxEntries: cacheMaxEntries,
+		},
+		Storage: StorageConfig{
+			Path:          os.Getenv("STORAGE_PATH"),
+			FreshFor:      storageFreshFor,
+			MaxAge:        storageMaxAge,
+			MaxSnapshots:  storageMaxSnapshots,
+			PruneInterval: storagePruneInterval,
+		},
+		Watch: WatchConfig{
+			Interval:   watchInterval,
+			MaxPerChat: watchMaxPerChat,
+		},
+		Live: LiveConfig{
+			Interval:   liveInterval,
+			MaxPerHour: liveMaxPerHour,
+		},
+		Webhook: WebhookConfig{
+			URL:            os.Getenv("WEBHOOK_URL"),
+			Listen:         getString("WEBHOOK_LISTEN", ":8443"),
+			PathSecret:     os.Getenv("WEBHOOK_PATH_SECRET"),
+			SecretToken:    os.Getenv("WEBHOOK_SECRET_TOKEN"),
+			CertFile:       os.Getenv("WEBHOOK_TLS_CERT"),
+			KeyFile:        os.Getenv("WEBHOOK_TLS_KEY"),
+			UploadCert:     webhookUploadCert,
+			MaxConnections: webhookMaxConnections,
+		},
+		Send: SendConfig{
+			GlobalPerSecond: sendGlobalPerSecond,
+			ChatInterval:    sendChatInterval,
+			GroupPerMinute:  sendGroupPerMinute,
+			MaxAttempts:     sendMaxAttempts,
+		},
+		RateLimit: RateLimitConfig{
+			UserBurst:    rateUserBurst,
+			UserInterval: rateUserInterval,
+			ChatBurst:    rateChatBurst,
+			ChatInterval: rateChatInterval,
+			DailyQuota:   rateDailyQuota,
+		},
+	}
+
+	for _, id := range adminIDs {
+		cfg.AdminIDs = append(cfg.AdminIDs, int64(id))
+	}
+
+	cfg.Fetcher.Retry, err = loadRetry("FETCHER_RETRY_", defaultRetry)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Fetcher.Retries = make(map[string]RetryConfig, len(fetcherPathVars))
+	for endpoint, key := range fetcherPathVars {
+		if path := os.Getenv(key); path != "" {
+			cfg.Fetcher.Paths[endpoint] = path
+		}
+
+		prefix := "FETCHER_RETRY_" + strings.ToUpper(endpoint) + "_"
+		if cfg.Fetcher.Retries[endpoint], err = loadRetry(prefix, cfg.Fetcher.Retry); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -37,5 +425,250 @@ func (c *Config) validate() error {
 		return fmt.Errorf("TELEGRAM_TOKEN is required")
 	}
 
+	if c.Workers < 1 || c.QueueSize < 1 {
+		return fmt.Errorf("WORKERS and QUEUE_SIZE must be positive")
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive")
+	}
+
+	if c.Send.GlobalPerSecond < 1 || c.Send.ChatInterval <= 0 || c.Send.GroupPerMinute < 1 || c.Send.MaxAttempts < 1 {
+		return fmt.Errorf("SEND_GLOBAL_PER_SECOND, SEND_CHAT_INTERVAL, SEND_GROUP_PER_MINUTE and SEND_MAX_ATTEMPTS must be positive")
+	}
+
+	if c.RateLimit.UserBurst < 0 || c.RateLimit.ChatBurst < 0 || c.RateLimit.DailyQuota < 0 {
+		return fmt.Errorf("RATE_USER_BURST, RATE_CHAT_BURST and RATE_DAILY_QUOTA must not be negative")
+	}
+
+	if (c.RateLimit.UserBurst > 0 && c.RateLimit.UserInterval <= 0) || (c.RateLimit.ChatBurst > 0 && c.RateLimit.ChatInterval <= 0) {
+		return fmt.Errorf("RATE_USER_INTERVAL and RATE_CHAT_INTERVAL must be positive")
+	}
+
+	if c.Batch.MaxChannels < 1 || c.Batch.Concurrency < 1 {
+		return fmt.Errorf("BATCH_MAX_CHANNELS and BATCH_CONCURRENCY must be positive")
+	}
+
+	if c.Pages.TTL <= 0 || c.Pages.MaxResults < 1 {
+		return fmt.Errorf("PAGE_TTL and PAGE_MAX_RESULTS must be positive")
+	}
+
+	if c.State.TTL <= 0 {
+		return fmt.Errorf("STATE_TTL must be positive")
+	}
+
+	if c.State.Persistent && c.Storage.Path == "" {
+		return fmt.Errorf("STATE_PERSISTENT requires STORAGE_PATH")
+	}
+
+	if c.Fetcher.BaseURL != "" && !strings.HasPrefix(c.Fetcher.BaseURL, "http://") && !strings.HasPrefix(c.Fetcher.BaseURL, "https://") {
+		return fmt.Errorf("FETCHER_BASE_URL must start with http:// or https://")
+	}
+
+	if c.Fetcher.Timeout <= 0 {
+		return fmt.Errorf("FETCHER_TIMEOUT must be positive")
+	}
+
+	if c.Cache.TTL < 0 || c.Cache.MaxEntries < 0 {
+		return fmt.Errorf("CACHE_TTL and CACHE_MAX_ENTRIES must not be negative")
+	}
+
+	if c.Storage.FreshFor < 0 || c.Storage.MaxAge < 0 || c.Storage.MaxSnapshots < 0 || c.Storage.PruneInterval < 0 {
+		return fmt.Errorf("STORAGE_FRESH_FOR, STORAGE_MAX_AGE, STORAGE_MAX_SNAPSHOTS and STORAGE_PRUNE_INTERVAL must not be negative")
+	}
+
+	if c.Watch.Interval < time.Minute {
+		return fmt.Errorf("WATCH_INTERVAL must be at least 1m")
+	}
+
+	if c.Watch.MaxPerChat < 0 {
+		return fmt.Errorf("WATCH_MAX_PER_CHAT must not be negative")
+	}
+
+	if c.Live.Interval < time.Minute {
+		return fmt.Errorf("LIVE_INTERVAL must be at least 1m")
+	}
+
+	if c.Live.MaxPerHour < 0 {
+		return fmt.Errorf("LIVE_MAX_PER_HOUR must not be negative")
+	}
+
+	if err := c.Webhook.validate(); err != nil {
+		return err
+	}
+
+	for endpoint, retry := range c.Fetcher.Retries {
+		if retry.MaxAttempts < 1 {
+			return fmt.Errorf("retry max attempts for %s must be at least 1", endpoint)
+		}
+		if retry.Jitter < 0 || retry.Jitter > 1 {
+			return fmt.Errorf("retry jitter for %s must be between 0 and 1", endpoint)
+		}
+	}
+
+	for endpoint, path := range c.Fetcher.Paths {
+		if !strings.Contains(path, "{username}") {
+			return fmt.Errorf("%s must contain the {username} placeholder", fetcherPathVars[endpoint])
+		}
+	}
+
 	return nil
 }
+
+// validate checks the webhook settings. Nothing is checked when the webhook is disabled.
+// Returns an error if any setting is invalid.
+func (w WebhookConfig) validate() error {
+	if w.URL == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(w.URL, "https://") {
+		return fmt.Errorf("WEBHOOK_URL must start with https://")
+	}
+
+	if w.Listen == "" {
+		return fmt.Errorf("WEBHOOK_LISTEN is required when WEBHOOK_URL is set")
+	}
+
+	if !webhookPathPattern.MatchString(w.PathSecret) {
+		return fmt.Errorf("WEBHOOK_PATH_SECRET may only contain letters, digits, '_' and '-'")
+	}
+
+	if w.SecretToken != "" && !secretTokenPattern.MatchString(w.SecretToken) {
+		return fmt.Errorf("WEBHOOK_SECRET_TOKEN must be 1-256 letters, digits, '_' or '-'")
+	}
+
+	if (w.CertFile == "") != (w.KeyFile == "") {
+		return fmt.Errorf("WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+	}
+
+	if w.UploadCert && w.CertFile == "" {
+		return fmt.Errorf("WEBHOOK_UPLOAD_CERT requires WEBHOOK_TLS_CERT")
+	}
+
+	if w.MaxConnections < 1 || w.MaxConnections > 100 {
+		return fmt.Errorf("WEBHOOK_MAX_CONNECTIONS must be between 1 and 100")
+	}
+
+	return nil
+}
+
+// getString reads a string environment variable, falling back to def when unset.
+func getString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// getDuration reads a duration environment variable, falling back to def when unset.
+// Returns an error if the value cannot be parsed.
+func getDuration(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid duration %q: %w", key, value, err)
+	}
+
+	return d, nil
+}
+
+// loadRetry reads a retry policy from environment variables with the given prefix
+// (e.g. FETCHER_RETRY_MAX_ATTEMPTS), falling back to def for unset values.
+// Returns an error if any value cannot be parsed.
+func loadRetry(prefix string, def RetryConfig) (RetryConfig, error) {
+	var (
+		retry = def
+		err   error
+	)
+
+	if retry.MaxAttempts, err = getInt(prefix+"MAX_ATTEMPTS", def.MaxAttempts); err != nil {
+		return retry, err
+	}
+	if retry.BaseDelay, err = getDuration(prefix+"BASE_DELAY", def.BaseDelay); err != nil {
+		return retry, err
+	}
+	if retry.MaxDelay, err = getDuration(prefix+"MAX_DELAY", def.MaxDelay); err != nil {
+		return retry, err
+	}
+	if retry.Jitter, err = getFloat(prefix+"JITTER", def.Jitter); err != nil {
+		return retry, err
+	}
+	if retry.RetryableStatus, err = getIntList(prefix+"STATUSES", def.RetryableStatus); err != nil {
+		return retry, err
+	}
+
+	return retry, nil
+}
+
+// getInt reads an integer environment variable, falling back to def when unset.
+// Returns an error if the value cannot be parsed.
+func getInt(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid integer %q: %w", key, value, err)
+	}
+
+	return n, nil
+}
+
+// getBool reads a boolean environment variable, falling back to def when unset.
+// Returns an error if the value cannot be parsed.
+func getBool(key string, def bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid boolean %q: %w", key, value, err)
+	}
+
+	return b, nil
+}
+
+// getFloat reads a floating-point environment variable, falling back to def when unset.
+// Returns an error if the value cannot be parsed.
+func getFloat(key string, def float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid number %q: %w", key, value, err)
+	}
+
+	return f, nil
+}
+
+// getIntList reads a comma-separated list of integers, falling back to def when unset.
+// Returns an error if any element cannot be parsed.
+func getIntList(key string, def []int) ([]int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	var list []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid integer %q: %w", key, part, err)
+		}
+		list = append(list, n)
+	}
+
+	return list, nil
+}